@@ -0,0 +1,118 @@
+package achievements
+
+import (
+	"context"
+	"github.com/amanzanero/wordleboard/api/models"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+type Service struct {
+	Logger *logrus.Logger
+	Repo   models.AchievementRepo
+}
+
+// evaluation is everything a rule can look at when a game finishes
+type evaluation struct {
+	board models.GameBoard
+	stats models.UserStatistics
+}
+
+type rule struct {
+	kind     models.AchievementKind
+	unlocked func(e evaluation) bool
+}
+
+var rules = []rule{
+	{
+		kind: models.AchievementKindFirstWin,
+		unlocked: func(e evaluation) bool {
+			return e.board.State == models.GameStateWon
+		},
+	},
+	{
+		kind: models.AchievementKindOneGuess,
+		unlocked: func(e evaluation) bool {
+			return e.board.State == models.GameStateWon && len(e.board.Guesses) == 1
+		},
+	},
+	{
+		kind: models.AchievementKindClutchWin,
+		unlocked: func(e evaluation) bool {
+			return e.board.State == models.GameStateWon && len(e.board.Guesses) == 6
+		},
+	},
+	{
+		kind: models.AchievementKindStreak7,
+		unlocked: func(e evaluation) bool {
+			return e.stats.Streak >= 7
+		},
+	},
+	{
+		kind: models.AchievementKindStreak30,
+		unlocked: func(e evaluation) bool {
+			return e.stats.Streak >= 30
+		},
+	},
+	{
+		kind: models.AchievementKindGames100,
+		unlocked: func(e evaluation) bool {
+			return e.stats.GamesPlayed >= 100
+		},
+	},
+}
+
+// EvaluateGame runs every rule against a finished daily game and the user's statistics, and
+// persists any achievements that weren't already unlocked. Only the newly unlocked ones are
+// returned.
+func (s *Service) EvaluateGame(ctx context.Context, userId string, board models.GameBoard) ([]models.Achievement, error) {
+	if board.State == models.GameStateInProgress {
+		return nil, nil
+	}
+
+	stats, statsErr := s.Repo.FindUserStatistics(ctx, userId)
+	if statsErr != nil {
+		return nil, statsErr
+	}
+	existing, findErr := s.Repo.FindAchievements(ctx, userId)
+	if findErr != nil {
+		return nil, findErr
+	}
+	alreadyUnlocked := make(map[models.AchievementKind]bool)
+	for _, achievement := range existing {
+		alreadyUnlocked[achievement.Kind] = true
+	}
+
+	e := evaluation{board: board, stats: *stats}
+	unlocked := make([]models.Achievement, 0)
+	now := time.Now().UTC()
+	for _, r := range rules {
+		if alreadyUnlocked[r.kind] || !r.unlocked(e) {
+			continue
+		}
+
+		achievement := models.Achievement{Kind: r.kind, UnlockedAt: now}
+		isNew, unlockErr := s.Repo.UnlockAchievement(ctx, userId, achievement)
+		if unlockErr != nil {
+			return nil, unlockErr
+		}
+		if isNew {
+			unlocked = append(unlocked, achievement)
+		}
+	}
+	return unlocked, nil
+}
+
+func (s *Service) GetAchievementsForUser(ctx context.Context, user models.User) ([]*models.Achievement, error) {
+	achievements, err := s.Repo.FindAchievements(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// pointerize
+	returnAchievements := make([]*models.Achievement, len(achievements))
+	for i := range achievements {
+		returnAchievements[i] = &achievements[i]
+	}
+	return returnAchievements, nil
+}
@@ -0,0 +1,49 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/amanzanero/wordleboard/api/models"
+	"github.com/amanzanero/wordleboard/api/users"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"strings"
+)
+
+// HasScope implements the @hasScope directive. Requests made with an access token need the scope,
+// requests signed in any other way don't.
+func HasScope(ctx context.Context, _ interface{}, next graphql.Resolver, scope models.TokenScope) (interface{}, error) {
+	accessToken := users.AccessTokenForContext(ctx)
+	if accessToken != nil && !accessToken.HasScope(scope) {
+		return nil, fmt.Errorf("access token is missing the %s scope", scope)
+	}
+	return next(ctx)
+}
+
+// RequireScopedRootFields keeps requests made with an access token to root fields marked with
+// @hasScope, so new fields aren't open to access tokens until they're given a scope
+func RequireScopedRootFields(ctx context.Context, next graphql.RootResolver) graphql.Marshaler {
+	if users.AccessTokenForContext(ctx) == nil {
+		return next(ctx)
+	}
+
+	field := graphql.GetRootFieldContext(ctx).Field
+	if strings.HasPrefix(field.Name, "__") || field.Definition.Directives.ForName("hasScope") != nil {
+		return next(ctx)
+	}
+	graphql.AddError(ctx, &gqlerror.Error{
+		Message: fmt.Sprintf("%s can't be used with an access token", field.Name),
+		Path:    ast.Path{ast.PathName(field.Alias)},
+	})
+	return graphql.Null
+}
+
+// HasRole implements the @hasRole directive. Access tokens never get a role, whatever their user has.
+func HasRole(ctx context.Context, _ interface{}, next graphql.Resolver, role models.Role) (interface{}, error) {
+	user := users.ForContext(ctx)
+	if user == nil || users.AccessTokenForContext(ctx) != nil || !user.HasRole(role) {
+		return nil, fmt.Errorf("the %s role is required", role)
+	}
+	return next(ctx)
+}
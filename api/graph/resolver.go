@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"github.com/amanzanero/wordleboard/api/achievements"
 	"github.com/amanzanero/wordleboard/api/leaderboards"
 	"github.com/amanzanero/wordleboard/api/users"
 	"github.com/amanzanero/wordleboard/api/wordle"
@@ -13,9 +14,10 @@ import (
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	WordleService      wordle.Service
-	UsersService       users.Service
-	LeaderboardService leaderboards.Service
-	Logger             *logrus.Logger
-	Timeout            time.Duration
+	WordleService       wordle.Service
+	UsersService        users.Service
+	LeaderboardService  leaderboards.Service
+	AchievementsService achievements.Service
+	Logger              *logrus.Logger
+	Timeout             time.Duration
 }
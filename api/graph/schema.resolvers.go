@@ -8,12 +8,153 @@ import (
 	"sync"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/amanzanero/wordleboard/api/graph/generated"
 	"github.com/amanzanero/wordleboard/api/logging"
 	"github.com/amanzanero/wordleboard/api/models"
 	"github.com/amanzanero/wordleboard/api/users"
+	"github.com/amanzanero/wordleboard/api/wordle"
 )
 
+func (r *adminMutationResolver) DeleteLeaderboard(ctx context.Context, obj *models.AdminMutation, joinID string) (bool, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "AdminDeleteLeaderboard", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	err := r.LeaderboardService.DeleteLeaderboard(cancelCtx, joinID)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in AdminDeleteLeaderboard: %v", err)
+	}
+	return err == nil, err
+}
+
+func (r *adminMutationResolver) BanUser(ctx context.Context, obj *models.AdminMutation, id string, reason *string) (*models.User, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "AdminBanUser", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	res, err := r.UsersService.BanUser(cancelCtx, id, reason)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in AdminBanUser: %v", err)
+	}
+	return res, err
+}
+
+func (r *adminMutationResolver) UnbanUser(ctx context.Context, obj *models.AdminMutation, id string) (*models.User, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "AdminUnbanUser", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	res, err := r.UsersService.UnbanUser(cancelCtx, id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in AdminUnbanUser: %v", err)
+	}
+	return res, err
+}
+
+func (r *adminMutationResolver) SetAdmin(ctx context.Context, obj *models.AdminMutation, id string, admin bool) (*models.User, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "AdminSetAdmin", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.UsersService.SetAdmin(cancelCtx, *user, id, admin)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in AdminSetAdmin: %v", err)
+	}
+	return res, err
+}
+
+func (r *adminQueryResolver) User(ctx context.Context, obj *models.AdminQuery, id string) (*models.User, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "AdminUser", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	res, err := r.UsersService.GetUserById(cancelCtx, id)
+	if _, isNotFound := err.(models.ErrNotFound); isNotFound {
+		return nil, nil
+	} else if err != nil {
+		logging.FromContext(ctx).Errorf("error in AdminUser: %v", err)
+	}
+	return res, err
+}
+
+func (r *adminQueryResolver) UserByUID(ctx context.Context, obj *models.AdminQuery, uid string) (*models.User, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "AdminUserByUID", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	res, err := r.UsersService.GetUserByOauthUuid(cancelCtx, uid)
+	if _, isNotFound := err.(models.ErrNotFound); isNotFound {
+		return nil, nil
+	} else if err != nil {
+		logging.FromContext(ctx).Errorf("error in AdminUserByUID: %v", err)
+	}
+	return res, err
+}
+
+func (r *adminQueryResolver) Leaderboard(ctx context.Context, obj *models.AdminQuery, joinID string) (models.LeaderboardResult, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "AdminLeaderboard", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	res, err := r.LeaderboardService.GetAnyLeaderboard(cancelCtx, joinID)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in AdminLeaderboard: %v", err)
+	}
+	return res, err
+}
+
+func (r *adminQueryResolver) Solution(ctx context.Context, obj *models.AdminQuery, day int) (*string, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "AdminSolution", time.Now())
+	return r.WordleService.GetSolution(day), nil
+}
+
+func (r *gameBoardResolver) Analysis(ctx context.Context, obj *models.GameBoard) (*models.BoardAnalysis, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "gameBoard.Analysis", time.Now())
+	res, err := r.WordleService.Analyze(*obj)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in gameBoard.Analysis: %v", err)
+	}
+	return res, err
+}
+
+func (r *gameBoardResolver) LetterStates(ctx context.Context, obj *models.GameBoard) ([]*models.LetterState, error) {
+	states := wordle.LetterStates(obj.Guesses)
+
+	// pointerize
+	returnStates := make([]*models.LetterState, len(states))
+	for i := range states {
+		returnStates[i] = &states[i]
+	}
+	return returnStates, nil
+}
+
+func (r *gameBoardResolver) ShareText(ctx context.Context, obj *models.GameBoard, palette *models.SharePalette, leaderboardID *string) (*string, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "gameBoard.ShareText", time.Now())
+	sharePalette := models.SharePaletteDefault
+	if palette != nil {
+		sharePalette = *palette
+	}
+
+	brand := ""
+	if leaderboardID != nil {
+		cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+
+		user := users.ForContext(ctx)
+		res, err := r.LeaderboardService.GetLeaderboard(cancelCtx, user.ID, *leaderboardID)
+		if err != nil {
+			logging.FromContext(ctx).Errorf("error in gameBoard.ShareText: %v", err)
+			return nil, err
+		}
+		if leaderboard, isLeaderboard := res.(*models.Leaderboard); isLeaderboard {
+			brand = leaderboard.Name
+		}
+	}
+	return wordle.ShareText(*obj, sharePalette, brand), nil
+}
+
 func (r *leaderboardResolver) Members(ctx context.Context, obj *models.Leaderboard) ([]*models.User, error) {
 	defer logging.LogTimeElapsed(logging.FromContext(ctx), "leaderboard.Members", time.Now())
 	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
@@ -30,42 +171,91 @@ func (r *leaderboardResolver) Stats(ctx context.Context, obj *models.Leaderboard
 	defer logging.LogTimeElapsed(logging.FromContext(ctx), "leaderboardResolver.Stats", time.Now())
 	user := users.ForContext(ctx)
 
+	stats, err := r.visibleLeaderboardStats(ctx, *obj, *user)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in leaderboard.Stats: %v", err)
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (r *leaderboardResolver) Standings(ctx context.Context, obj *models.Leaderboard, rankBy *models.LeaderboardRanking) ([]*models.Standing, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "leaderboardResolver.Standings", time.Now())
+	user := users.ForContext(ctx)
+
+	stats, err := r.visibleLeaderboardStats(ctx, *obj, *user)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in leaderboard.Standings: %v", err)
+		return nil, err
+	}
+
+	ranking := models.LeaderboardRankingGuesses
+	if rankBy != nil {
+		ranking = *rankBy
+	}
+	return r.LeaderboardService.GetStandings(stats, ranking, obj.Settings), nil
+}
+
+func (r *leaderboardResolver) MultiBoardStats(ctx context.Context, obj *models.Leaderboard, variant models.MultiBoardVariant, first *int, after *int) ([]*models.MultiBoardLeaderboardStat, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "leaderboardResolver.MultiBoardStats", time.Now())
+	user := users.ForContext(ctx)
+
 	var wg sync.WaitGroup
-	var stats []*models.LeaderboardStat
+	var stats []*models.MultiBoardLeaderboardStat
 	var statsErr error
-	var todayBoard *models.GameBoard
+	var todayBoard *models.MultiBoard
 	var todayBoardErr error
 
 	wg.Add(2)
 	go func() {
-		stats, statsErr = r.LeaderboardService.GetStatsForLeaderboard(ctx, *obj)
+		stats, statsErr = r.LeaderboardService.GetMultiBoardStatsForLeaderboard(ctx, *obj, variant)
 		wg.Done()
 	}()
 	go func() {
-		todayBoard, todayBoardErr = r.WordleService.GetTodayGameOrCreateNewGame(ctx, user.ID, time.Now())
+		todayBoard, todayBoardErr = r.WordleService.GetTodayMultiBoardOrCreate(ctx, user.ID, variant, time.Now())
 		wg.Done()
 	}()
 	wg.Wait()
 
 	if statsErr != nil {
-		logging.FromContext(ctx).Errorf("error in leaderboard.Stats: %v", statsErr)
+		logging.FromContext(ctx).Errorf("error in leaderboard.MultiBoardStats: %v", statsErr)
 		return nil, statsErr
 	}
 	if todayBoardErr != nil {
-		logging.FromContext(ctx).Errorf("error in leaderboard.Stats: %v", todayBoardErr)
+		logging.FromContext(ctx).Errorf("error in leaderboard.MultiBoardStats: %v", todayBoardErr)
 		return nil, todayBoardErr
 	}
 
 	for _, stat := range stats {
-		if stat.Day == todayBoard.Day && todayBoard.State == models.GameStateInProgress {
-			stat.Visible = false
-		} else {
-			stat.Visible = true
-		}
+		stat.Visible = !(stat.Day == todayBoard.Day && todayBoard.State == models.GameStateInProgress)
 	}
 	return stats, nil
 }
 
+func (r *leaderboardResolver) AbsurdleStats(ctx context.Context, obj *models.Leaderboard) ([]*models.AbsurdleStat, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "leaderboard.AbsurdleStats", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	res, err := r.LeaderboardService.GetAbsurdleStatsForLeaderboard(cancelCtx, *obj)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in leaderboard.AbsurdleStats: %v", err)
+	}
+	return res, err
+}
+
+func (r *leaderboardResolver) Puzzles(ctx context.Context, obj *models.Leaderboard) ([]*models.Puzzle, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "leaderboard.Puzzles", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	res, err := r.LeaderboardService.GetPuzzlesForLeaderboard(cancelCtx, *obj)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in leaderboard.Puzzles: %v", err)
+	}
+	return res, err
+}
+
 func (r *mutationResolver) Guess(ctx context.Context, input string) (models.GuessResult, error) {
 	defer logging.LogTimeElapsed(logging.FromContext(ctx), "Guess", time.Now())
 	user := users.ForContext(ctx)
@@ -117,6 +307,265 @@ func (r *mutationResolver) LeaveLeaderboard(ctx context.Context, id string) (boo
 	return err == nil, err
 }
 
+func (r *mutationResolver) StartPractice(ctx context.Context) (*models.PracticeBoard, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "StartPractice", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.WordleService.StartPractice(cancelCtx, user.ID, time.Now())
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in StartPractice: %v", err)
+	}
+	return res, err
+}
+
+func (r *mutationResolver) PracticeGuess(ctx context.Context, id string, input string) (models.GuessResult, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "PracticeGuess", time.Now())
+	user := users.ForContext(ctx)
+	board, err := r.WordleService.PracticeGuess(ctx, user.ID, id, input)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("practiceGuess mutation failed: %v", err)
+		return nil, err
+	}
+	return board, nil
+}
+
+func (r *mutationResolver) CreatePuzzle(ctx context.Context, solution string) (models.PuzzleResult, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "CreatePuzzle", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.WordleService.CreatePuzzle(cancelCtx, user.ID, solution)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in CreatePuzzle: %v", err)
+	}
+	return res, err
+}
+
+func (r *mutationResolver) AttachPuzzle(ctx context.Context, id string, leaderboardID string) (models.LeaderboardResult, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "AttachPuzzle", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.LeaderboardService.AttachPuzzle(cancelCtx, user.ID, id, leaderboardID)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in AttachPuzzle: %v", err)
+	}
+	return res, err
+}
+
+func (r *mutationResolver) PuzzleGuess(ctx context.Context, id string, input string) (models.GuessResult, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "PuzzleGuess", time.Now())
+	user := users.ForContext(ctx)
+	board, err := r.WordleService.PuzzleGuess(ctx, user.ID, id, input)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("puzzleGuess mutation failed: %v", err)
+		return nil, err
+	}
+	return board, nil
+}
+
+func (r *mutationResolver) MultiGuess(ctx context.Context, variant models.MultiBoardVariant, input string) (models.GuessResult, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "MultiGuess", time.Now())
+	user := users.ForContext(ctx)
+	board, err := r.WordleService.MultiGuess(ctx, user.ID, variant, input)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("multiGuess mutation failed: %v", err)
+		return nil, err
+	}
+	return board, nil
+}
+
+func (r *mutationResolver) StartAbsurdle(ctx context.Context) (*models.AbsurdleBoard, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "StartAbsurdle", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.WordleService.StartAbsurdle(cancelCtx, user.ID)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in StartAbsurdle: %v", err)
+	}
+	return res, err
+}
+
+func (r *mutationResolver) AbsurdleGuess(ctx context.Context, id string, input string) (models.GuessResult, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "AbsurdleGuess", time.Now())
+	user := users.ForContext(ctx)
+	board, err := r.WordleService.AbsurdleGuess(ctx, user.ID, id, input)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("absurdleGuess mutation failed: %v", err)
+		return nil, err
+	}
+	return board, nil
+}
+
+func (r *mutationResolver) RequestHint(ctx context.Context, kind models.HintKind) (models.HintResult, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "RequestHint", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.WordleService.RequestHint(cancelCtx, user.ID, kind)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in RequestHint: %v", err)
+	}
+	return res, err
+}
+
+func (r *mutationResolver) UpdateLeaderboardSettings(ctx context.Context, id string, input models.LeaderboardSettingsInput) (models.LeaderboardResult, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "UpdateLeaderboardSettings", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.LeaderboardService.UpdateSettings(cancelCtx, user.ID, id, input)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in UpdateLeaderboardSettings: %v", err)
+	}
+	return res, err
+}
+
+func (r *mutationResolver) ImportShareText(ctx context.Context, text string) (*models.ImportResult, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "ImportShareText", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.WordleService.ImportShareText(cancelCtx, user.ID, text)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in ImportShareText: %v", err)
+	}
+	return res, err
+}
+
+func (r *mutationResolver) MergeGuest(ctx context.Context, guestToken string) (*models.User, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "MergeGuest", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.UsersService.MergeGuest(cancelCtx, *user, guestToken)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in MergeGuest: %v", err)
+	}
+	return res, err
+}
+
+func (r *mutationResolver) LinkIdentity(ctx context.Context, token string) (*models.User, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "LinkIdentity", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.UsersService.LinkIdentity(cancelCtx, *user, token)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in LinkIdentity: %v", err)
+	}
+	return res, err
+}
+
+func (r *mutationResolver) UpdateProfile(ctx context.Context, input models.ProfileInput) (models.ProfileResult, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "UpdateProfile", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.UsersService.UpdateProfile(cancelCtx, *user, input)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in UpdateProfile: %v", err)
+	}
+	return res, err
+}
+
+func (r *mutationResolver) UploadAvatar(ctx context.Context, file graphql.Upload) (models.ProfileResult, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "UploadAvatar", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.UsersService.UploadAvatar(cancelCtx, *user, file.File)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in UploadAvatar: %v", err)
+	}
+	return res, err
+}
+
+func (r *mutationResolver) CreateAccessToken(ctx context.Context, name string, scopes []models.TokenScope) (*models.NewAccessToken, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "CreateAccessToken", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.UsersService.CreateAccessToken(cancelCtx, *user, name, scopes)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in CreateAccessToken: %v", err)
+	}
+	return res, err
+}
+
+func (r *mutationResolver) RevokeAccessToken(ctx context.Context, id string) (bool, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "RevokeAccessToken", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	err := r.UsersService.RevokeAccessToken(cancelCtx, *user, id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in RevokeAccessToken: %v", err)
+	}
+	return err == nil, err
+}
+
+func (r *mutationResolver) DeleteAccount(ctx context.Context) (bool, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "DeleteAccount", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	err := r.UsersService.DeleteAccount(cancelCtx, *user)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in DeleteAccount: %v", err)
+	}
+	return err == nil, err
+}
+
+func (r *mutationResolver) Admin(ctx context.Context) (*models.AdminMutation, error) {
+	return &models.AdminMutation{}, nil
+}
+
+func (r *puzzleResolver) Board(ctx context.Context, obj *models.Puzzle) (*models.PuzzleBoard, error) {
+	user := users.ForContext(ctx)
+	board := obj.BoardForUser(user.ID)
+	return &board, nil
+}
+
+func (r *puzzleResolver) Results(ctx context.Context, obj *models.Puzzle) ([]*models.PuzzleStat, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "puzzle.Results", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	// nobody, including the creator, sees other results before finishing the puzzle
+	user := users.ForContext(ctx)
+	if obj.BoardForUser(user.ID).State == models.GameStateInProgress {
+		return make([]*models.PuzzleStat, 0), nil
+	}
+
+	res, err := r.LeaderboardService.GetPuzzleResults(cancelCtx, *obj)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in puzzle.Results: %v", err)
+	}
+	return res, err
+}
+
+func (r *puzzleResolver) Visible(ctx context.Context, obj *models.Puzzle) (bool, error) {
+	user := users.ForContext(ctx)
+	return obj.BoardForUser(user.ID).State != models.GameStateInProgress, nil
+}
+
 func (r *queryResolver) Day(ctx context.Context, input int) (*models.GameBoard, error) {
 	defer logging.LogTimeElapsed(logging.FromContext(ctx), "Day", time.Now())
 	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
@@ -136,7 +585,7 @@ func (r *queryResolver) TodayBoard(ctx context.Context) (*models.GameBoard, erro
 	defer cancel()
 
 	user := users.ForContext(ctx)
-	res, err := r.WordleService.GetTodayGameOrCreateNewGame(cancelCtx, user.ID, time.Now())
+	res, err := r.WordleService.GetTodayGameOrCreateNewGame(cancelCtx, *user, time.Now())
 	if err != nil {
 		logging.FromContext(ctx).Errorf("error in TodayBoard: %v", err)
 	}
@@ -161,6 +610,93 @@ func (r *queryResolver) Leaderboard(ctx context.Context, joinID string) (models.
 	return res, err
 }
 
+func (r *queryResolver) PracticeBoard(ctx context.Context, id string) (*models.PracticeBoard, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "PracticeBoard", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.WordleService.GetPracticeBoard(cancelCtx, user.ID, id)
+	if _, isNotFound := err.(models.ErrNotFound); isNotFound {
+		return nil, nil
+	} else if err != nil {
+		logging.FromContext(ctx).Errorf("error in PracticeBoard: %v", err)
+	}
+	return res, err
+}
+
+func (r *queryResolver) Puzzle(ctx context.Context, id string) (*models.Puzzle, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "Puzzle", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	res, err := r.WordleService.GetPuzzle(cancelCtx, id)
+	if _, isNotFound := err.(models.ErrNotFound); isNotFound {
+		return nil, nil
+	} else if err != nil {
+		logging.FromContext(ctx).Errorf("error in Puzzle: %v", err)
+	}
+	return res, err
+}
+
+func (r *queryResolver) TodayMultiBoard(ctx context.Context, variant models.MultiBoardVariant) (*models.MultiBoard, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "TodayMultiBoard", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.WordleService.GetTodayMultiBoardOrCreate(cancelCtx, user.ID, variant, time.Now())
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in TodayMultiBoard: %v", err)
+	}
+	return res, err
+}
+
+func (r *queryResolver) AbsurdleBoard(ctx context.Context, id string) (*models.AbsurdleBoard, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "AbsurdleBoard", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.WordleService.GetAbsurdleBoard(cancelCtx, user.ID, id)
+	if _, isNotFound := err.(models.ErrNotFound); isNotFound {
+		return nil, nil
+	} else if err != nil {
+		logging.FromContext(ctx).Errorf("error in AbsurdleBoard: %v", err)
+	}
+	return res, err
+}
+
+func (r *queryResolver) ExportMyData(ctx context.Context) (string, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "ExportMyData", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.UsersService.ExportData(cancelCtx, *user)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in ExportMyData: %v", err)
+	}
+	return res, err
+}
+
+func (r *queryResolver) AccessTokens(ctx context.Context) ([]*models.AccessToken, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "AccessTokens", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	user := users.ForContext(ctx)
+	res, err := r.UsersService.GetAccessTokens(cancelCtx, *user)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in AccessTokens: %v", err)
+	}
+	return res, err
+}
+
+func (r *queryResolver) Admin(ctx context.Context) (*models.AdminQuery, error) {
+	return &models.AdminQuery{}, nil
+}
+
 func (r *userResolver) Leaderboards(ctx context.Context, obj *models.User) ([]*models.Leaderboard, error) {
 	defer logging.LogTimeElapsed(logging.FromContext(ctx), "user.Leaderboards", time.Now())
 	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
@@ -183,19 +719,57 @@ func (r *userResolver) IndividualStats(ctx context.Context, obj *models.User, fi
 	return res, err
 }
 
+func (r *userResolver) Statistics(ctx context.Context, obj *models.User) (*models.UserStatistics, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "user.Statistics", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+	res, err := r.LeaderboardService.GetStatisticsForUser(cancelCtx, *obj, wordle.DayOf(time.Now()))
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in user.Statistics: %v", err)
+	}
+	return res, err
+}
+
+func (r *userResolver) Achievements(ctx context.Context, obj *models.User) ([]*models.Achievement, error) {
+	defer logging.LogTimeElapsed(logging.FromContext(ctx), "user.Achievements", time.Now())
+	cancelCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+	res, err := r.AchievementsService.GetAchievementsForUser(cancelCtx, *obj)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error in user.Achievements: %v", err)
+	}
+	return res, err
+}
+
+// AdminMutation returns generated.AdminMutationResolver implementation.
+func (r *Resolver) AdminMutation() generated.AdminMutationResolver { return &adminMutationResolver{r} }
+
+// AdminQuery returns generated.AdminQueryResolver implementation.
+func (r *Resolver) AdminQuery() generated.AdminQueryResolver { return &adminQueryResolver{r} }
+
+// GameBoard returns generated.GameBoardResolver implementation.
+func (r *Resolver) GameBoard() generated.GameBoardResolver { return &gameBoardResolver{r} }
+
 // Leaderboard returns generated.LeaderboardResolver implementation.
 func (r *Resolver) Leaderboard() generated.LeaderboardResolver { return &leaderboardResolver{r} }
 
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
+// Puzzle returns generated.PuzzleResolver implementation.
+func (r *Resolver) Puzzle() generated.PuzzleResolver { return &puzzleResolver{r} }
+
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
 // User returns generated.UserResolver implementation.
 func (r *Resolver) User() generated.UserResolver { return &userResolver{r} }
 
+type adminMutationResolver struct{ *Resolver }
+type adminQueryResolver struct{ *Resolver }
+type gameBoardResolver struct{ *Resolver }
 type leaderboardResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
+type puzzleResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
 type userResolver struct{ *Resolver }
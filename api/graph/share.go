@@ -0,0 +1,111 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"github.com/amanzanero/wordleboard/api/logging"
+	"github.com/amanzanero/wordleboard/api/models"
+	"github.com/amanzanero/wordleboard/api/render"
+	"github.com/amanzanero/wordleboard/api/users"
+	"github.com/amanzanero/wordleboard/api/wordle"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// BoardImageHandler serves one of the user's finished boards as a PNG card. Boards that aren't
+// finished aren't served, the same as their share text. REQUIRES AuthMiddleware to have run.
+func (r *Resolver) BoardImageHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		day, parseErr := strconv.Atoi(chi.URLParam(req, "day"))
+		if parseErr != nil {
+			http.Error(w, "invalid day", http.StatusBadRequest)
+			return
+		}
+		palette := models.SharePalette(strings.ToUpper(req.URL.Query().Get("palette")))
+		if !palette.IsValid() {
+			palette = models.SharePaletteDefault
+		}
+
+		ctx, cancel := context.WithTimeout(req.Context(), r.Timeout)
+		defer cancel()
+
+		user := users.ForContext(ctx)
+		board, err := r.WordleService.GetGameByDay(ctx, user.ID, day)
+		if _, isNotFound := err.(models.ErrNotFound); isNotFound {
+			http.Error(w, "board not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			logging.FromContext(ctx).Errorf("error in BoardImageHandler: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		shareText := wordle.ShareText(*board, palette, "")
+		if shareText == nil {
+			http.Error(w, "board not finished", http.StatusNotFound)
+			return
+		}
+		title := strings.SplitN(*shareText, "\n", 2)[0]
+
+		var image bytes.Buffer
+		if renderErr := render.BoardPNG(&image, title, board.Guesses, palette); renderErr != nil {
+			logging.FromContext(ctx).Errorf("error in BoardImageHandler: %v", renderErr)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		writePNG(w, image.Bytes())
+	}
+}
+
+// LeaderboardImageHandler serves a leaderboard's standings as a PNG card. Only members can see it,
+// and today's results are left out until the user has finished today's board, just like the
+// leaderboard's stats. REQUIRES AuthMiddleware to have run.
+func (r *Resolver) LeaderboardImageHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithTimeout(req.Context(), r.Timeout)
+		defer cancel()
+
+		user := users.ForContext(ctx)
+		res, err := r.LeaderboardService.GetLeaderboard(ctx, user.ID, chi.URLParam(req, "id"))
+		if err != nil {
+			logging.FromContext(ctx).Errorf("error in LeaderboardImageHandler: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		leaderboard, isLeaderboard := res.(*models.Leaderboard)
+		if !isLeaderboard {
+			if res.(models.LeaderboardResultError).Error == models.LeaderboardErrorNotAuthorized {
+				http.Error(w, "not a member", http.StatusForbidden)
+			} else {
+				http.Error(w, "leaderboard not found", http.StatusNotFound)
+			}
+			return
+		}
+
+		stats, statsErr := r.visibleLeaderboardStats(ctx, *leaderboard, *user)
+		if statsErr != nil {
+			logging.FromContext(ctx).Errorf("error in LeaderboardImageHandler: %v", statsErr)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		standings := r.LeaderboardService.GetStandings(stats, models.LeaderboardRankingGuesses, leaderboard.Settings)
+
+		var image bytes.Buffer
+		if renderErr := render.StandingsPNG(&image, leaderboard.Name, standings); renderErr != nil {
+			logging.FromContext(ctx).Errorf("error in LeaderboardImageHandler: %v", renderErr)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		writePNG(w, image.Bytes())
+	}
+}
+
+func writePNG(w http.ResponseWriter, image []byte) {
+	w.Header().Set("Content-Type", "image/png")
+	// images depend on who's asking, so they can't be shared by caches
+	w.Header().Set("Cache-Control", "private, max-age=60")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(image)
+}
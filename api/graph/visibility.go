@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"context"
+	"github.com/amanzanero/wordleboard/api/models"
+	"sync"
+	"time"
+)
+
+// visibleLeaderboardStats fetches a leaderboard's stats and marks today's results as hidden until
+// the user has finished today's board
+func (r *Resolver) visibleLeaderboardStats(ctx context.Context, lb models.Leaderboard, user models.User) ([]*models.LeaderboardStat, error) {
+	var wg sync.WaitGroup
+	var stats []*models.LeaderboardStat
+	var statsErr error
+	var todayBoard *models.GameBoard
+	var todayBoardErr error
+
+	wg.Add(2)
+	go func() {
+		stats, statsErr = r.LeaderboardService.GetStatsForLeaderboard(ctx, lb)
+		wg.Done()
+	}()
+	go func() {
+		todayBoard, todayBoardErr = r.WordleService.GetTodayGameOrCreateNewGame(ctx, user, time.Now())
+		wg.Done()
+	}()
+	wg.Wait()
+
+	if statsErr != nil {
+		return nil, statsErr
+	}
+	if todayBoardErr != nil {
+		return nil, todayBoardErr
+	}
+
+	for _, stat := range stats {
+		if stat.Day == todayBoard.Day && todayBoard.State == models.GameStateInProgress {
+			stat.Visible = false
+		} else {
+			stat.Visible = true
+		}
+	}
+	return stats, nil
+}
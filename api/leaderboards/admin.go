@@ -0,0 +1,23 @@
+package leaderboards
+
+import (
+	"context"
+	"github.com/amanzanero/wordleboard/api/models"
+)
+
+// GetAnyLeaderboard finds a leaderboard without checking the user is in it, for admins
+func (s *Service) GetAnyLeaderboard(ctx context.Context, boardId string) (models.LeaderboardResult, error) {
+	board, findErr := s.Repo.FindLeaderboardByJoinId(ctx, boardId)
+	if _, isNotFound := findErr.(models.ErrNotFound); isNotFound {
+		return models.LeaderboardResultError{Error: models.LeaderboardErrorDoesNotExist}, nil
+	} else if findErr != nil {
+		return nil, findErr
+	}
+	return board, nil
+}
+
+// DeleteLeaderboard deletes a leaderboard for everyone in it. Puzzles attached to it are kept, and
+// only detached from it.
+func (s *Service) DeleteLeaderboard(ctx context.Context, boardId string) error {
+	return s.Repo.DeleteLeaderboard(ctx, boardId)
+}
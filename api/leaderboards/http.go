@@ -0,0 +1,145 @@
+package leaderboards
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/logging"
+	"github.com/amanzanero/wordleboard/api/models"
+	"github.com/amanzanero/wordleboard/api/render"
+	"github.com/go-chi/chi/v5"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// previewTimeout bounds the lookups for a preview, since these endpoints aren't authenticated
+const previewTimeout = 5 * time.Second
+
+// invitePreview is everything an invite link preview shows. It's all that's exposed to people who
+// aren't members, so nothing private belongs here.
+type invitePreview struct {
+	JoinId      string
+	Name        string
+	MemberCount int
+	OwnerName   string
+}
+
+// getInvitePreview looks up what an invite link preview shows about a leaderboard
+func (s *Service) getInvitePreview(ctx context.Context, joinId string) (*invitePreview, error) {
+	lb, err := s.Repo.FindLeaderboardByJoinId(ctx, joinId)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &invitePreview{
+		JoinId:      lb.ID,
+		Name:        lb.Name,
+		MemberCount: len(lb.MemberIds),
+	}
+	owners, ownerErr := s.Repo.FindLeaderBoardMembers(ctx, []string{lb.Owner})
+	if ownerErr != nil {
+		return nil, ownerErr
+	}
+	if len(owners) > 0 {
+		preview.OwnerName = owners[0].DisplayName
+	}
+	return preview, nil
+}
+
+var invitePageTemplate = template.Must(template.New("invite").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Join {{.Name}} on wordleboard</title>
+<meta property="og:type" content="website">
+<meta property="og:site_name" content="wordleboard">
+<meta property="og:title" content="Join {{.Name}} on wordleboard">
+<meta property="og:description" content="{{.Description}}">
+<meta property="og:url" content="{{.JoinUrl}}">
+<meta property="og:image" content="{{.ImageUrl}}">
+<meta property="og:image:width" content="1200">
+<meta property="og:image:height" content="630">
+<meta name="twitter:card" content="summary_large_image">
+<meta http-equiv="refresh" content="0; url={{.JoinUrl}}">
+</head>
+<body><a href="{{.JoinUrl}}">Join {{.Name}}</a></body>
+</html>
+`))
+
+// InvitePageHandler serves OpenGraph metadata for a leaderboard invite, so links unfurl in chats.
+// People that open the link are sent on to the join page. It doesn't need authentication.
+func (s *Service) InvitePageHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), previewTimeout)
+		defer cancel()
+
+		preview, err := s.getInvitePreview(ctx, chi.URLParam(r, "id"))
+		if !s.previewFound(w, r, err) {
+			return
+		}
+
+		origin := strings.TrimSuffix(s.PublicUrl, "/")
+		members := fmt.Sprintf("%d members", preview.MemberCount)
+		if preview.MemberCount == 1 {
+			members = "1 member"
+		}
+		page := struct {
+			Name        string
+			Description string
+			JoinUrl     string
+			ImageUrl    string
+		}{
+			Name:        preview.Name,
+			Description: fmt.Sprintf("%s, owned by %s", members, preview.OwnerName),
+			JoinUrl:     fmt.Sprintf("%s/leaderboards/join/%s", origin, preview.JoinId),
+			ImageUrl:    fmt.Sprintf("%s/api/og/leaderboard/%s.png", origin, preview.JoinId),
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		if renderErr := invitePageTemplate.Execute(w, page); renderErr != nil {
+			logging.FromContext(ctx).Errorf("error in InvitePageHandler: %v", renderErr)
+		}
+	}
+}
+
+// InviteImageHandler serves the preview image for a leaderboard invite. It doesn't need
+// authentication.
+func (s *Service) InviteImageHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), previewTimeout)
+		defer cancel()
+
+		preview, err := s.getInvitePreview(ctx, chi.URLParam(r, "id"))
+		if !s.previewFound(w, r, err) {
+			return
+		}
+
+		var image bytes.Buffer
+		if renderErr := render.InvitePNG(&image, preview.Name, preview.MemberCount, preview.OwnerName); renderErr != nil {
+			logging.FromContext(ctx).Errorf("error in InviteImageHandler: %v", renderErr)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		_, _ = w.Write(image.Bytes())
+	}
+}
+
+// previewFound writes the error response for a failed preview lookup, and reports whether the
+// lookup succeeded
+func (s *Service) previewFound(w http.ResponseWriter, r *http.Request, err error) bool {
+	if err == nil {
+		return true
+	}
+	if _, isNotFound := err.(models.ErrNotFound); isNotFound {
+		http.Error(w, "leaderboard not found", http.StatusNotFound)
+	} else {
+		logging.FromContext(r.Context()).Errorf("could not look up invite preview: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+	return false
+}
@@ -11,6 +11,8 @@ import (
 type Service struct {
 	Logger *logrus.Logger
 	Repo   models.LeaderboardRepo
+	// PublicUrl is the origin people open the site at, links to the site are made from it
+	PublicUrl string
 }
 
 func (s *Service) CreateNewLeaderboard(ctx context.Context, owner, name string) (models.LeaderboardResult, error) {
@@ -19,6 +21,7 @@ func (s *Service) CreateNewLeaderboard(ctx context.Context, owner, name string)
 		MemberIds: make([]string, 1),
 		Owner:     owner,
 		ID:        shortuuid.New(),
+		Settings:  models.DefaultLeaderboardSettings(),
 	}
 	modelToInsert.MemberIds[0] = owner
 	lb, err := s.Repo.InsertNewLeaderboard(ctx, modelToInsert)
@@ -120,6 +123,9 @@ func (s *Service) GetStatsForLeaderboard(ctx context.Context, lb models.Leaderbo
 	dayToStat := make(map[int]models.LeaderboardStat)
 	for _, stats := range userStats {
 		for _, stat := range stats {
+			if stat.Imported && !lb.Settings.IncludeImported {
+				continue
+			}
 			if entry, ok := dayToStat[stat.Day]; !ok {
 				newLeaderboardStat := models.LeaderboardStat{
 					Day:   stat.Day,
@@ -145,6 +151,7 @@ func (s *Service) GetStatsForLeaderboard(ctx context.Context, lb models.Leaderbo
 	returnStats := make([]*models.LeaderboardStat, len(lbStats))
 	for i := 0; i < len(returnStats); i += 1 {
 		returnStats[i] = &lbStats[i]
+		returnStats[i].Difficulty = dayDifficulty(returnStats[i], lb.Settings)
 	}
 
 	sort.Slice(returnStats, func(i, j int) bool {
@@ -167,12 +174,192 @@ func (s *Service) GetStatsForUser(ctx context.Context, user models.User) ([]*mod
 	stats := make([]*models.UserStat, len(gameBoards))
 	for i, gb := range gameBoards {
 		stats[i] = &models.UserStat{
-			Day:     gb.Day,
-			Guesses: gb.Guesses,
-			State:   gb.State,
-			User:    user,
+			Day:          gb.Day,
+			Guesses:      gb.Guesses,
+			State:        gb.State,
+			User:         user,
+			FirstGuessAt: gb.FirstGuessAt,
+			CompletedAt:  gb.CompletedAt,
+			HintsUsed:    len(gb.Hints),
+			Imported:     gb.Imported,
 		}
 	}
 
 	return stats, nil
 }
+
+// AttachPuzzle adds a custom puzzle to a leaderboard so members' results show up there. Only the
+// puzzle's creator can attach it, and only to a leaderboard they belong to.
+func (s *Service) AttachPuzzle(ctx context.Context, userId, puzzleId, boardId string) (models.LeaderboardResult, error) {
+	puzzle, findErr := s.Repo.FindPuzzle(ctx, puzzleId)
+	if findErr != nil {
+		if _, isNotFound := findErr.(models.ErrNotFound); isNotFound {
+			return models.LeaderboardResultError{Error: models.LeaderboardErrorDoesNotExist}, nil
+		}
+		return nil, findErr
+	}
+	if puzzle.Creator != userId {
+		return models.LeaderboardResultError{Error: models.LeaderboardErrorNotAuthorized}, nil
+	}
+
+	res, err := s.GetLeaderboard(ctx, userId, boardId)
+	if err != nil {
+		return nil, err
+	}
+	board, isLeaderboard := res.(*models.Leaderboard)
+	if !isLeaderboard {
+		return res, nil
+	}
+
+	attachErr := s.Repo.AttachPuzzleToLeaderboard(ctx, puzzle.ID, board.ID)
+	if attachErr != nil {
+		return nil, attachErr
+	}
+	return board, nil
+}
+
+// maxHintPenalty keeps a single hint from costing more than losing the game outright
+const maxHintPenalty = failedGameScore
+
+// UpdateSettings changes how a leaderboard scores hints and whether it counts imported games. Only the owner can change settings.
+func (s *Service) UpdateSettings(ctx context.Context, userId, boardId string, input models.LeaderboardSettingsInput) (models.LeaderboardResult, error) {
+	board, findErr := s.Repo.FindLeaderboardByJoinId(ctx, boardId)
+	if findErr != nil {
+		if _, isNotFound := findErr.(models.ErrNotFound); isNotFound {
+			return models.LeaderboardResultError{Error: models.LeaderboardErrorDoesNotExist}, nil
+		}
+		return nil, findErr
+	}
+	if board.Owner != userId {
+		return models.LeaderboardResultError{Error: models.LeaderboardErrorNotAuthorized}, nil
+	}
+
+	if input.HintsAllowed != nil {
+		board.Settings.HintsAllowed = *input.HintsAllowed
+	}
+	if input.HintPenalty != nil {
+		if *input.HintPenalty < 0 || *input.HintPenalty > maxHintPenalty {
+			return models.LeaderboardResultError{Error: models.LeaderboardErrorInvalidInput}, nil
+		}
+		board.Settings.HintPenalty = *input.HintPenalty
+	}
+	if input.IncludeImported != nil {
+		board.Settings.IncludeImported = *input.IncludeImported
+	}
+
+	saveErr := s.Repo.UpdateLeaderboardById(ctx, board.StoredId, *board)
+	if saveErr != nil {
+		return nil, saveErr
+	}
+	return board, nil
+}
+
+func (s *Service) GetPuzzlesForLeaderboard(ctx context.Context, lb models.Leaderboard) ([]*models.Puzzle, error) {
+	return s.Repo.FindPuzzlesForLeaderboard(ctx, lb.ID)
+}
+
+// GetPuzzleResults returns every finished or in progress board for a puzzle. Callers are
+// responsible for only showing these to users that have finished the puzzle themselves.
+func (s *Service) GetPuzzleResults(ctx context.Context, puzzle models.Puzzle) ([]*models.PuzzleStat, error) {
+	userIds := make([]string, len(puzzle.Boards))
+	for i, board := range puzzle.Boards {
+		userIds[i] = board.UserId
+	}
+	players, err := s.Repo.FindLeaderBoardMembers(ctx, userIds)
+	if err != nil {
+		return nil, err
+	}
+	idToPlayer := make(map[string]*models.User)
+	for _, player := range players {
+		idToPlayer[player.ID] = player
+	}
+
+	stats := make([]*models.PuzzleStat, 0)
+	for _, board := range puzzle.Boards {
+		player, ok := idToPlayer[board.UserId]
+		if !ok {
+			continue
+		}
+		stats = append(stats, &models.PuzzleStat{
+			User:    *player,
+			Guesses: board.Guesses,
+			State:   board.State,
+		})
+	}
+	return stats, nil
+}
+
+// GetMultiBoardStatsForLeaderboard groups the members' multi-board results by day. Each variant is
+// tracked separately from the others and from the daily board.
+func (s *Service) GetMultiBoardStatsForLeaderboard(ctx context.Context, lb models.Leaderboard, variant models.MultiBoardVariant) ([]*models.MultiBoardLeaderboardStat, error) {
+	userStats, err := s.Repo.FindMultiBoardStatsForMembers(ctx, lb.MemberIds, variant)
+	if err != nil {
+		return nil, models.ErrRepoFailed{RepoMethod: "GetMultiBoardStatsForLeaderboard", Message: err.Error()}
+	}
+
+	dayToStat := make(map[int]*models.MultiBoardLeaderboardStat)
+	for _, stats := range userStats {
+		for _, stat := range stats {
+			entry, ok := dayToStat[stat.Day]
+			if !ok {
+				entry = &models.MultiBoardLeaderboardStat{
+					Day:   stat.Day,
+					Stats: make([]models.MultiBoardUserStat, 0),
+				}
+				dayToStat[stat.Day] = entry
+			}
+			entry.Stats = append(entry.Stats, stat)
+		}
+	}
+
+	returnStats := make([]*models.MultiBoardLeaderboardStat, 0, len(dayToStat))
+	for _, lbStat := range dayToStat {
+		returnStats = append(returnStats, lbStat)
+	}
+	sort.Slice(returnStats, func(i, j int) bool {
+		return returnStats[i].Day > returnStats[j].Day
+	})
+	return returnStats, nil
+}
+
+// GetAbsurdleStatsForLeaderboard compares how many guesses each member needed to corner the
+// adversarial solver, fewest guesses first
+func (s *Service) GetAbsurdleStatsForLeaderboard(ctx context.Context, lb models.Leaderboard) ([]*models.AbsurdleStat, error) {
+	userBoards, err := s.Repo.FindAbsurdleBoardsForMembers(ctx, lb.MemberIds)
+	if err != nil {
+		return nil, models.ErrRepoFailed{RepoMethod: "GetAbsurdleStatsForLeaderboard", Message: err.Error()}
+	}
+
+	stats := make([]*models.AbsurdleStat, 0, len(userBoards))
+	for user, boards := range userBoards {
+		stat := &models.AbsurdleStat{User: user, GamesPlayed: len(boards)}
+		totalGuesses := 0
+		for _, board := range boards {
+			if board.State != models.GameStateWon {
+				continue
+			}
+			guessCount := len(board.Guesses)
+			stat.GamesWon += 1
+			totalGuesses += guessCount
+			if stat.BestGuesses == nil || guessCount < *stat.BestGuesses {
+				stat.BestGuesses = &guessCount
+			}
+		}
+		if stat.GamesWon > 0 {
+			average := float64(totalGuesses) / float64(stat.GamesWon)
+			stat.AverageGuesses = &average
+		}
+		stats = append(stats, stat)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].BestGuesses == nil || stats[j].BestGuesses == nil {
+			return stats[i].BestGuesses != nil
+		}
+		if *stats[i].BestGuesses != *stats[j].BestGuesses {
+			return *stats[i].BestGuesses < *stats[j].BestGuesses
+		}
+		return stats[i].GamesWon > stats[j].GamesWon
+	})
+	return stats, nil
+}
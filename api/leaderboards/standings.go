@@ -0,0 +1,162 @@
+package leaderboards
+
+import (
+	"github.com/amanzanero/wordleboard/api/models"
+	"sort"
+)
+
+// failedGameScore is what a lost game counts as when averaging guesses
+const failedGameScore = 7
+
+type standingTotals struct {
+	standing     *models.Standing
+	totalScore   int
+	totalSeconds int
+	timedWins    int
+	totalSkill   float64
+	skillGames   int
+}
+
+// gameScore is the number of guesses a finished game took, with losses counting as
+// failedGameScore. Hints add the leaderboard's penalty, and count as a loss on leaderboards that
+// don't allow them.
+func gameScore(stat models.UserStat, settings models.LeaderboardSettings) int {
+	if !countsAsWin(stat, settings) {
+		return failedGameScore
+	}
+	score := len(stat.Guesses) + stat.HintsUsed*settings.HintPenalty
+	if score > failedGameScore {
+		return failedGameScore
+	}
+	return score
+}
+
+func countsAsWin(stat models.UserStat, settings models.LeaderboardSettings) bool {
+	if stat.State != models.GameStateWon {
+		return false
+	}
+	return settings.HintsAllowed || stat.HintsUsed == 0
+}
+
+// dayDifficulty is the average score of everyone that finished the day, or nil if nobody has
+func dayDifficulty(dayStat *models.LeaderboardStat, settings models.LeaderboardSettings) *float64 {
+	total, finished := 0, 0
+	for _, stat := range dayStat.Stats {
+		if stat.State == models.GameStateInProgress {
+			continue
+		}
+		total += gameScore(stat, settings)
+		finished += 1
+	}
+	if finished == 0 {
+		return nil
+	}
+	difficulty := float64(total) / float64(finished)
+	return &difficulty
+}
+
+// GetStandings ranks a leaderboard's members across every visible day of its stats. Days that are
+// hidden from the current user are left out, so standings never leak today's results. Skill is
+// measured against each day's difficulty, so a hard day doesn't count against anyone. Hints are
+// scored by the leaderboard's settings.
+func (s *Service) GetStandings(stats []*models.LeaderboardStat, rankBy models.LeaderboardRanking, settings models.LeaderboardSettings) []*models.Standing {
+	userToTotals := make(map[string]*standingTotals)
+	for _, dayStat := range stats {
+		if !dayStat.Visible {
+			continue
+		}
+		difficulty := dayDifficulty(dayStat, settings)
+		for _, stat := range dayStat.Stats {
+			if stat.State == models.GameStateInProgress {
+				continue
+			}
+			totals, ok := userToTotals[stat.User.ID]
+			if !ok {
+				totals = &standingTotals{standing: &models.Standing{User: stat.User}}
+				userToTotals[stat.User.ID] = totals
+			}
+			totals.add(stat, *difficulty, settings)
+		}
+	}
+
+	standings := make([]*models.Standing, 0, len(userToTotals))
+	for _, totals := range userToTotals {
+		standings = append(standings, totals.finish())
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		return standingLess(standings[i], standings[j], rankBy)
+	})
+	for i, standing := range standings {
+		standing.Rank = i + 1
+	}
+	return standings
+}
+
+func (t *standingTotals) add(stat models.UserStat, difficulty float64, settings models.LeaderboardSettings) {
+	score := gameScore(stat, settings)
+	t.standing.GamesPlayed += 1
+	t.totalScore += score
+	t.totalSkill += difficulty - float64(score)
+	t.skillGames += 1
+	if !countsAsWin(stat, settings) {
+		return
+	}
+
+	t.standing.GamesWon += 1
+	if seconds := stat.SolveDuration(); seconds != nil {
+		t.totalSeconds += *seconds
+		t.timedWins += 1
+		if t.standing.FastestSolveSeconds == nil || *seconds < *t.standing.FastestSolveSeconds {
+			t.standing.FastestSolveSeconds = seconds
+		}
+	}
+}
+
+func (t *standingTotals) finish() *models.Standing {
+	if t.standing.GamesPlayed > 0 {
+		averageGuesses := float64(t.totalScore) / float64(t.standing.GamesPlayed)
+		t.standing.AverageGuesses = &averageGuesses
+	}
+	if t.skillGames > 0 {
+		skill := t.totalSkill / float64(t.skillGames)
+		t.standing.SkillRating = &skill
+	}
+	if t.timedWins > 0 {
+		averageSeconds := float64(t.totalSeconds) / float64(t.timedWins)
+		t.standing.AverageSolveSeconds = &averageSeconds
+	}
+	return t.standing
+}
+
+// standingLess orders standings by the chosen ranking, with missing values last
+func standingLess(a, b *models.Standing, rankBy models.LeaderboardRanking) bool {
+	var aValue, bValue *float64
+	switch rankBy {
+	case models.LeaderboardRankingSpeed:
+		aValue, bValue = a.AverageSolveSeconds, b.AverageSolveSeconds
+	case models.LeaderboardRankingSkill:
+		// higher skill ranks first
+		aValue, bValue = negate(a.SkillRating), negate(b.SkillRating)
+	default:
+		aValue, bValue = a.AverageGuesses, b.AverageGuesses
+	}
+
+	if (aValue == nil) != (bValue == nil) {
+		return aValue != nil
+	}
+	if aValue != nil && *aValue != *bValue {
+		return *aValue < *bValue
+	}
+	if a.GamesWon != b.GamesWon {
+		return a.GamesWon > b.GamesWon
+	}
+	return a.User.ID < b.User.ID
+}
+
+func negate(value *float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	negated := -*value
+	return &negated
+}
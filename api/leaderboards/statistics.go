@@ -0,0 +1,88 @@
+package leaderboards
+
+import (
+	"context"
+	"github.com/amanzanero/wordleboard/api/models"
+)
+
+// statisticsRetries is how many times a conflicting statistics update is retried
+const statisticsRetries = 3
+
+// GetStatisticsForUser returns a user's lifetime statistics, with the current streak as of today.
+// Statistics are computed from the user's boards the first time they're read, and kept up to date
+// by RecordGameResult after that.
+func (s *Service) GetStatisticsForUser(ctx context.Context, user models.User, today int) (*models.UserStatistics, error) {
+	stats, err := s.Repo.FindUserStatistics(ctx, user.ID)
+	if _, isNotFound := err.(models.ErrNotFound); isNotFound {
+		stats, err = s.RecomputeStatistics(ctx, user.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	stats.CurrentStreak = stats.StreakAsOf(today)
+	return stats, nil
+}
+
+// RecordGameResult adds a finished daily game to the user's statistics
+func (s *Service) RecordGameResult(ctx context.Context, userId string, board models.GameBoard) error {
+	if board.State == models.GameStateInProgress {
+		return nil
+	}
+
+	for i := 0; i < statisticsRetries; i++ {
+		previous, err := s.Repo.FindUserStatistics(ctx, userId)
+		if _, isNotFound := err.(models.ErrNotFound); isNotFound {
+			// the board is already saved, so computing from scratch will include it
+			_, err = s.RecomputeStatistics(ctx, userId)
+			return err
+		} else if err != nil {
+			return err
+		}
+
+		if !previous.CanRecord(board.Day) {
+			_, err = s.RecomputeStatistics(ctx, userId)
+			return err
+		}
+
+		stats := *previous
+		stats.Distribution = append([]int(nil), previous.Distribution...)
+		stats.Record(board.Day, board.State, len(board.Guesses))
+		err = s.Repo.SaveUserStatistics(ctx, userId, stats, previous)
+		if _, isConflict := err.(models.ErrConflict); !isConflict {
+			return err
+		}
+	}
+	return models.ErrRepoFailed{RepoMethod: "RecordGameResult", Message: "statistics kept changing while being updated"}
+}
+
+// RecomputeStatistics rebuilds a user's statistics from every board they've played
+func (s *Service) RecomputeStatistics(ctx context.Context, userId string) (*models.UserStatistics, error) {
+	boards, err := s.Repo.FindGameBoardsForUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := models.ComputeUserStatistics(boards)
+	previous, findErr := s.Repo.FindUserStatistics(ctx, userId)
+	if _, isNotFound := findErr.(models.ErrNotFound); isNotFound {
+		previous = nil
+	} else if findErr != nil {
+		return nil, findErr
+	}
+
+	saveErr := s.Repo.SaveUserStatistics(ctx, userId, stats, previous)
+	if _, isConflict := saveErr.(models.ErrConflict); isConflict {
+		// someone else saved statistics first, theirs were computed from the same boards
+		s.Logger.Infof("statistics for user %s were saved concurrently", userId)
+	} else if saveErr != nil {
+		return nil, saveErr
+	}
+	return &stats, nil
+}
+
+// ClearStatistics throws away a user's statistics, so they're recomputed the next time they're read.
+// It's used when a finished game couldn't be recorded.
+func (s *Service) ClearStatistics(ctx context.Context, userId string) error {
+	return s.Repo.ClearUserStatistics(ctx, userId)
+}
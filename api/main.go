@@ -2,11 +2,14 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"flag"
 	"fmt"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/amanzanero/wordleboard/api/achievements"
 	"github.com/amanzanero/wordleboard/api/leaderboards"
 	"github.com/amanzanero/wordleboard/api/logging"
+	"github.com/amanzanero/wordleboard/api/models"
 	"github.com/amanzanero/wordleboard/api/mongo"
 	"github.com/amanzanero/wordleboard/api/secrets"
 	"github.com/amanzanero/wordleboard/api/users"
@@ -16,8 +19,10 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // time zone preferences are checked against it, and the image has no zoneinfo
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/amanzanero/wordleboard/api/graph"
@@ -68,38 +73,146 @@ func main() {
 		}
 	}()
 
-	authClient, authClientErr := users.NewAuthClient(context.Background(), secretManager)
-	if authClientErr != nil {
-		logger.Fatalf("failed to initialize auth.Client: %v", authClientErr)
+	var identityProvider users.IdentityProvider
+	usesFirebase := secretManager.GetSecretString(secrets.AuthProvider) != "local"
+	if usesFirebase {
+		identityProvider, err = users.NewFirebaseProvider(context.Background(), secretManager)
+	} else {
+		identityProvider, err = users.NewLocalJWTProvider([]byte(secretManager.GetSecretString(secrets.LocalJWTKey)))
+	}
+	if err != nil {
+		logger.Fatalf("failed to initialize identity provider: %v", err)
+	}
+	userService := users.Service{
+		Identity:     identityProvider,
+		Repo:         mongoService,
+		AccessTokens: mongoService,
+		Logger:       logger,
+		AuthCache:    users.NewAuthCache(10000, time.Minute),
 	}
-	userService := users.Service{Client: authClient, Repo: mongoService, Logger: logger}
+
+	// first-party accounts and guests sign their sessions with the local key, so they need one
+	sessionsEnabled := secretManager.GetSecretString(secrets.LocalJWTKey) != ""
+	if sessionsEnabled {
+		sessions, sessionsErr := users.NewLocalJWTProvider([]byte(secretManager.GetSecretString(secrets.LocalJWTKey)))
+		if sessionsErr != nil {
+			logger.Fatalf("failed to initialize account sessions: %v", sessionsErr)
+		}
+		var emailSender users.EmailSender = &users.WriterEmailSender{W: os.Stdout}
+		if outbox := secretManager.GetSecretString(secrets.EmailOutbox); outbox != "" {
+			emailSender = users.FileEmailSender{Dir: outbox}
+		}
+
+		userService.Identity = users.ChainedProvider{
+			users.AccountProvider{Repo: mongoService, Sessions: sessions},
+			users.GuestProvider{Sessions: sessions},
+			identityProvider,
+		}
+		userService.Accounts = mongoService
+		userService.Sessions = sessions
+		userService.Email = emailSender
+		userService.PublicUrl = secretManager.GetSecretString(secrets.PublicUrl)
+		userService.AccountIPLimiter = users.NewRateLimiter(30, 15*time.Minute)
+		userService.AccountEmailLimiter = users.NewRateLimiter(10, 15*time.Minute)
+	}
+	blobDir := secretManager.GetSecretString(secrets.BlobDir)
+	blobStore := users.LocalBlobStore{
+		Dir:     blobDir,
+		BaseUrl: strings.TrimSuffix(secretManager.GetSecretString(secrets.PublicUrl), "/") + "/api/blobs",
+	}
+	if blobDir != "" {
+		userService.Blobs = blobStore
+	}
+	// users can only be provisioned safely once the oauth_uuid index is unique, and it can only be
+	// made unique once users created by the old race are merged. Admins are promoted after that.
+	func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if mergeErr := userService.MergeDuplicateUsers(ctx); mergeErr != nil {
+			logger.Fatalf("failed to merge duplicate users: %v", mergeErr)
+		}
+		if indexErr := mongoService.CreateUniqueUserIndexes(ctx); indexErr != nil {
+			logger.Fatalf("failed to create unique user indexes: %v", indexErr)
+		}
+		adminUids := strings.Split(secretManager.GetSecretString(secrets.AdminUids), ",")
+		if promoteErr := userService.PromoteAdmins(ctx, adminUids); promoteErr != nil {
+			logger.Fatalf("failed to promote admins: %v", promoteErr)
+		}
+	}()
+	// deletions and merges that stopped part way are finished in the background
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if resumeErr := userService.ResumeDeletions(ctx); resumeErr != nil {
+			logger.Errorf("failed to resume user deletions: %v", resumeErr)
+		}
+		if resumeErr := userService.ResumeMerges(ctx); resumeErr != nil {
+			logger.Errorf("failed to resume user merges: %v", resumeErr)
+		}
+	}()
 	leaderboardService := leaderboards.Service{
+		Logger:    logger,
+		Repo:      mongoService,
+		PublicUrl: secretManager.GetSecretString(secrets.PublicUrl),
+	}
+	achievementsService := achievements.Service{
 		Logger: logger,
 		Repo:   mongoService,
 	}
 	resolver := &graph.Resolver{
 		WordleService: wordle.NewService(
 			mongoService,
+			&leaderboardService,
+			&achievementsService,
 			logger,
 		),
-		UsersService:       userService,
-		LeaderboardService: leaderboardService,
-		Logger:             logger,
-		Timeout:            15 * time.Second,
+		AchievementsService: achievementsService,
+		UsersService:        userService,
+		LeaderboardService:  leaderboardService,
+		Logger:              logger,
+		Timeout:             15 * time.Second,
 	}
-	gqlServer := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
+	gqlServer := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{
+		Resolvers:  resolver,
+		Directives: generated.DirectiveRoot{HasScope: graph.HasScope, HasRole: graph.HasRole},
+	}))
+	gqlServer.AroundRootFields(graph.RequireScopedRootFields)
 
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
 	r.Use(logging.HttpLoggingMiddleware(logger, *isDev))
 	r.Handle("/graphql", userService.AuthMiddleware(gqlServer))
 	r.Post("/api/users", userService.CreateUserHandler())
+	if sessionsEnabled {
+		r.Post("/api/accounts/register", userService.RegisterHandler())
+		r.Post("/api/accounts/login", userService.LoginHandler())
+		r.Post("/api/accounts/password-reset", userService.PasswordResetHandler())
+		r.Post("/api/accounts/password-reset/confirm", userService.ConfirmPasswordResetHandler())
+		r.Post("/api/accounts/magic-link", userService.MagicLinkHandler())
+		r.Post("/api/accounts/magic-link/confirm", userService.ConfirmMagicLinkHandler())
+		r.Post("/api/guests", userService.GuestHandler())
+	}
+	r.Get("/api/share/board/{day}.png", userService.AuthMiddleware(
+		users.RequireScope(models.TokenScopeReadStats, resolver.BoardImageHandler()),
+	))
+	r.Get("/api/share/leaderboard/{id}.png", userService.AuthMiddleware(
+		users.RequireScope(models.TokenScopeReadStats, resolver.LeaderboardImageHandler()),
+	))
+	r.Get("/api/og/leaderboard/{id}", leaderboardService.InvitePageHandler())
+	r.Get("/api/og/leaderboard/{id}.png", leaderboardService.InviteImageHandler())
+	if blobDir != "" {
+		r.Handle("/api/blobs/*", http.StripPrefix("/api/blobs/", blobStore.Handler()))
+	}
 
 	if *isDev {
-		r.Get("/api/token/{uid}", userService.AccessToken(secretManager.GetSecretString(secrets.FirebaseEndpoint)))
+		if usesFirebase {
+			r.Get("/api/token/{uid}", userService.AccessToken(secretManager.GetSecretString(secrets.FirebaseEndpoint)))
+		}
 		r.Get("/api/customToken/{uid}", userService.CustomToken())
 		r.Handle("/graphiql", playground.Handler("GraphQL playground", "/graphql"))
 	}
+	// metrics like the auth cache's hit rate are only for admins
+	r.Handle("/debug/vars", userService.AuthMiddleware(users.RequireRole(models.RoleAdmin, expvar.Handler())))
 
 	httpServer := &http.Server{
 		Addr:    fmt.Sprintf(":%s", secretManager.GetSecretString(secrets.Port)),
@@ -116,7 +229,7 @@ func main() {
 	}()
 	logger.Infof("http server running on port %s", secretManager.GetSecretString(secrets.Port))
 
-	interrupted := make(chan os.Signal)
+	interrupted := make(chan os.Signal, 1)
 	signal.Notify(interrupted, syscall.SIGTERM, os.Interrupt)
 
 	select {
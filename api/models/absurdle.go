@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// AbsurdleBoard is an adversarial game, where the server never commits to a solution and keeps as
+// many candidates alive as it can after every guess
+type AbsurdleBoard struct {
+	ID          string         `json:"id"`
+	UserId      string         `json:"-"`
+	Guesses     [][]GuessState `json:"guesses"`
+	State       GameState      `json:"state"`
+	Remaining   int            `json:"remaining"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	CompletedAt *time.Time     `json:"completedAt"`
+}
+
+func (AbsurdleBoard) IsGuessResult() {}
+
+type AbsurdleStat struct {
+	User           User     `json:"user"`
+	GamesPlayed    int      `json:"gamesPlayed"`
+	GamesWon       int      `json:"gamesWon"`
+	BestGuesses    *int     `json:"bestGuesses"`
+	AverageGuesses *float64 `json:"averageGuesses"`
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type AccessTokenRepo interface {
+	InsertAccessToken(ctx context.Context, token AccessToken) (*AccessToken, error)
+	FindAccessTokenByHash(ctx context.Context, hash string) (*AccessToken, error)
+	FindAccessTokensForUser(ctx context.Context, userId string) ([]*AccessToken, error)
+	TouchAccessToken(ctx context.Context, id string, at time.Time) error
+	RevokeAccessToken(ctx context.Context, userId, id string, at time.Time) error
+	DeleteAccessTokensForUser(ctx context.Context, userId string) error
+	TransferAccessTokens(ctx context.Context, fromUserId, toUserId string) error
+}
+
+// AccessToken is a long-lived token a user creates for scripts and bots. It can only do what its
+// scopes allow. Only a hash of the token is stored, it's shown to the user once when it's created.
+type AccessToken struct {
+	ID         string       `json:"id"`
+	UserId     string       `json:"-"`
+	Name       string       `json:"name"`
+	Hash       string       `json:"-"`
+	Scopes     []TokenScope `json:"scopes"`
+	CreatedAt  time.Time    `json:"createdAt"`
+	LastUsedAt *time.Time   `json:"lastUsedAt"`
+	RevokedAt  *time.Time   `json:"revokedAt"`
+}
+
+func (t AccessToken) HasScope(scope TokenScope) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type NewAccessToken struct {
+	Token       string       `json:"token"`
+	AccessToken *AccessToken `json:"accessToken"`
+}
+
+type TokenScope string
+
+const (
+	TokenScopeReadStats          TokenScope = "READ_STATS"
+	TokenScopeSubmitGuesses      TokenScope = "SUBMIT_GUESSES"
+	TokenScopeManageLeaderboards TokenScope = "MANAGE_LEADERBOARDS"
+)
+
+var AllTokenScope = []TokenScope{
+	TokenScopeReadStats,
+	TokenScopeSubmitGuesses,
+	TokenScopeManageLeaderboards,
+}
+
+func (e TokenScope) IsValid() bool {
+	switch e {
+	case TokenScopeReadStats, TokenScopeSubmitGuesses, TokenScopeManageLeaderboards:
+		return true
+	}
+	return false
+}
+
+func (e TokenScope) String() string {
+	return string(e)
+}
+
+func (e *TokenScope) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TokenScope(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TokenScope", str)
+	}
+	return nil
+}
+
+func (e TokenScope) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
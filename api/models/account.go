@@ -0,0 +1,50 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+type AccountRepo interface {
+	InsertAccount(ctx context.Context, account Account) (*Account, error)
+	FindAccountByEmail(ctx context.Context, email string) (*Account, error)
+	FindAccountByUid(ctx context.Context, uid string) (*Account, error)
+	UpdateAccountPassword(ctx context.Context, uid, passwordHash string, sessionsNotBefore time.Time) error
+	InsertLoginToken(ctx context.Context, token LoginToken) error
+	ConsumeLoginToken(ctx context.Context, tokenHash string, purpose LoginTokenPurpose, now time.Time) (*LoginToken, error)
+	DeleteAccounts(ctx context.Context, uids []string) error
+}
+
+// Account is a first-party sign in, for people that don't want to use a social login. Its UID
+// links it to a User the same way an oauth uid does.
+type Account struct {
+	ID           string
+	UID          string
+	Email        string
+	DisplayName  string
+	PasswordHash string // empty for accounts that only sign in with magic links
+	CreatedAt    time.Time
+
+	// SessionsNotBefore signs out every session issued before it, it's set when the password is reset
+	SessionsNotBefore *time.Time
+}
+
+// LoginToken is a single use token emailed to an account. Only its hash is stored. Magic links for
+// emails without an account have no AccountUid, the account is created from the rest of the token
+// once the link is used.
+type LoginToken struct {
+	Hash         string
+	AccountUid   string
+	Purpose      LoginTokenPurpose
+	ExpiresAt    time.Time
+	Email        string
+	DisplayName  string
+	PasswordHash string
+}
+
+type LoginTokenPurpose string
+
+const (
+	LoginTokenPurposePasswordReset LoginTokenPurpose = "password_reset"
+	LoginTokenPurposeMagicLink     LoginTokenPurpose = "magic_link"
+)
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type AchievementRepo interface {
+	FindAchievements(ctx context.Context, userId string) ([]Achievement, error)
+	UnlockAchievement(ctx context.Context, userId string, achievement Achievement) (bool, error)
+	FindUserStatistics(ctx context.Context, userId string) (*UserStatistics, error)
+}
+
+// AchievementEvaluator checks for newly unlocked achievements once a daily game is finished
+type AchievementEvaluator interface {
+	EvaluateGame(ctx context.Context, userId string, board GameBoard) ([]Achievement, error)
+}
+
+type Achievement struct {
+	Kind       AchievementKind `json:"kind"`
+	UnlockedAt time.Time       `json:"unlockedAt"`
+}
+
+type AchievementKind string
+
+const (
+	AchievementKindFirstWin  AchievementKind = "FIRST_WIN"
+	AchievementKindOneGuess  AchievementKind = "ONE_GUESS"
+	AchievementKindClutchWin AchievementKind = "CLUTCH_WIN"
+	AchievementKindStreak7   AchievementKind = "STREAK_7"
+	AchievementKindStreak30  AchievementKind = "STREAK_30"
+	AchievementKindGames100  AchievementKind = "GAMES_100"
+)
+
+var AllAchievementKind = []AchievementKind{
+	AchievementKindFirstWin,
+	AchievementKindOneGuess,
+	AchievementKindClutchWin,
+	AchievementKindStreak7,
+	AchievementKindStreak30,
+	AchievementKindGames100,
+}
+
+func (e AchievementKind) IsValid() bool {
+	switch e {
+	case AchievementKindFirstWin,
+		AchievementKindOneGuess,
+		AchievementKindClutchWin,
+		AchievementKindStreak7,
+		AchievementKindStreak30,
+		AchievementKindGames100:
+		return true
+	}
+	return false
+}
+
+func (e AchievementKind) String() string {
+	return string(e)
+}
+
+func (e *AchievementKind) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AchievementKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AchievementKind", str)
+	}
+	return nil
+}
+
+func (e AchievementKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,18 @@
+package models
+
+// BoardAnalysis grades each guess of a finished board against the solutions that were still
+// possible when it was played
+type BoardAnalysis struct {
+	Day  int           `json:"day"`
+	Rows []RowAnalysis `json:"rows"`
+}
+
+type RowAnalysis struct {
+	Guess                 string  `json:"guess"`
+	CandidatesBefore      int     `json:"candidatesBefore"`
+	CandidatesAfter       int     `json:"candidatesAfter"`
+	BitsGained            float64 `json:"bitsGained"`
+	ExpectedBits          float64 `json:"expectedBits"`
+	BestGuess             string  `json:"bestGuess"`
+	BestGuessExpectedBits float64 `json:"bestGuessExpectedBits"`
+}
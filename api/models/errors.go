@@ -21,3 +21,23 @@ type ErrRepoFailed struct {
 func (r ErrRepoFailed) Error() string {
 	return fmt.Sprintf("RepoFailed (%s): %s", r.RepoMethod, r.Message)
 }
+
+type ErrAlreadyExists struct {
+	RepoMethod string
+	Message    string
+}
+
+func (e ErrAlreadyExists) Error() string {
+	return fmt.Sprintf("AlreadyExists (%s): %s", e.RepoMethod, e.Message)
+}
+
+// ErrConflict is returned when a write is rejected because what it was based on changed since it
+// was read
+type ErrConflict struct {
+	RepoMethod string
+	Message    string
+}
+
+func (c ErrConflict) Error() string {
+	return fmt.Sprintf("Conflict (%s): %s", c.RepoMethod, c.Message)
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// UserDataExport is everything stored about a user, as it's stored. Secrets like password hashes
+// and other users' data are left out.
+type UserDataExport struct {
+	ExportedAt     time.Time         `json:"exportedAt"`
+	User           json.RawMessage   `json:"user"`
+	Accounts       []json.RawMessage `json:"accounts"`
+	AccessTokens   []json.RawMessage `json:"accessTokens"`
+	Leaderboards   []json.RawMessage `json:"leaderboards"`
+	PracticeBoards []json.RawMessage `json:"practiceBoards"`
+	AbsurdleBoards []json.RawMessage `json:"absurdleBoards"`
+	Puzzles        []json.RawMessage `json:"puzzles"`      // puzzles the user created
+	PuzzleBoards   []json.RawMessage `json:"puzzleBoards"` // the user's boards on any puzzle
+}
@@ -5,18 +5,61 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"time"
 )
 
 type GameBoardRepo interface {
 	FindGameBoardByUserAndDay(ctx context.Context, userId string, day int) (*GameBoard, error)
 	InsertGameBoard(ctx context.Context, userId string, gameBoard GameBoard) error
-	UpdateGameBoardByUserAndDay(ctx context.Context, day int, userId string, gameBoard GameBoard) error
+	InsertGameBoardIfMissing(ctx context.Context, userId string, gameBoard GameBoard) error
+	AddGameBoardGuess(ctx context.Context, userId string, gameBoard GameBoard) error
+	AddGameBoardHint(ctx context.Context, userId string, gameBoard GameBoard) error
+	InsertPracticeBoard(ctx context.Context, board PracticeBoard) error
+	FindPracticeBoard(ctx context.Context, userId, id string) (*PracticeBoard, error)
+	UpdatePracticeBoard(ctx context.Context, board PracticeBoard) error
+	InsertPuzzle(ctx context.Context, puzzle Puzzle) error
+	FindPuzzle(ctx context.Context, id string) (*Puzzle, error)
+	UpsertPuzzleBoard(ctx context.Context, board PuzzleBoard) error
+	FindMultiBoardByUserAndDay(ctx context.Context, userId string, day int, variant MultiBoardVariant) (*MultiBoard, error)
+	InsertMultiBoard(ctx context.Context, userId string, board MultiBoard) error
+	UpdateMultiBoard(ctx context.Context, userId string, board MultiBoard) error
+	InsertAbsurdleBoard(ctx context.Context, board AbsurdleBoard) error
+	FindAbsurdleBoard(ctx context.Context, userId, id string) (*AbsurdleBoard, error)
+	UpdateAbsurdleBoard(ctx context.Context, board AbsurdleBoard) error
 }
 
 type GameBoard struct {
 	Day     int            `json:"day"`
 	Guesses [][]GuessState `json:"guesses"`
 	State   GameState      `json:"state"`
+	Hints   []Hint         `json:"hints"`
+
+	// Imported boards were pasted from the official game's share text, so their guesses only
+	// have patterns and no letters
+	Imported bool `json:"imported"`
+
+	// HardMode is set from the user's preference when the board starts. Every guess on a hard mode
+	// board has to use the letters earlier guesses revealed.
+	HardMode bool `json:"hardMode"`
+
+	// timestamps are recorded by the server, so solve times can't be tampered with
+	CreatedAt    time.Time   `json:"createdAt"`
+	FirstGuessAt *time.Time  `json:"firstGuessAt"`
+	GuessTimes   []time.Time `json:"guessTimes"`
+	CompletedAt  *time.Time  `json:"completedAt"`
+
+	// UnlockedAchievements is only filled in on the guess that finished the game
+	UnlockedAchievements []Achievement `json:"unlockedAchievements"`
+}
+
+// solveSeconds is the time between the first guess and the end of the game, or nil if the game
+// isn't over or was played before timestamps were recorded
+func solveSeconds(firstGuessAt, completedAt *time.Time) *int {
+	if firstGuessAt == nil || completedAt == nil {
+		return nil
+	}
+	seconds := int(completedAt.Sub(*firstGuessAt).Seconds())
+	return &seconds
 }
 
 type GuessState struct {
@@ -127,16 +170,18 @@ type GuessError string
 const (
 	GuessErrorNotAWord      GuessError = "NotAWord"
 	GuessErrorInvalidLength GuessError = "InvalidLength"
+	GuessErrorHardMode      GuessError = "HardMode"
 )
 
 var AllGuessError = []GuessError{
 	GuessErrorNotAWord,
 	GuessErrorInvalidLength,
+	GuessErrorHardMode,
 }
 
 func (e GuessError) IsValid() bool {
 	switch e {
-	case GuessErrorNotAWord, GuessErrorInvalidLength:
+	case GuessErrorNotAWord, GuessErrorInvalidLength, GuessErrorHardMode:
 		return true
 	}
 	return false
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Hint reveals part of a board's solution. A position hint reveals the letter at a position, a
+// letter hint reveals a letter without saying where it goes.
+type Hint struct {
+	Kind        HintKind  `json:"kind"`
+	Letter      string    `json:"letter"`
+	Position    *int      `json:"position"`
+	RequestedAt time.Time `json:"requestedAt"`
+}
+
+func (Hint) IsHintResult() {}
+
+type HintResult interface {
+	IsHintResult()
+}
+
+type HintResultError struct {
+	Error HintError `json:"error"`
+}
+
+func (HintResultError) IsHintResult() {}
+
+type HintKind string
+
+const (
+	HintKindLetter   HintKind = "LETTER"
+	HintKindPosition HintKind = "POSITION"
+)
+
+var AllHintKind = []HintKind{
+	HintKindLetter,
+	HintKindPosition,
+}
+
+func (e HintKind) IsValid() bool {
+	switch e {
+	case HintKindLetter, HintKindPosition:
+		return true
+	}
+	return false
+}
+
+func (e HintKind) String() string {
+	return string(e)
+}
+
+func (e *HintKind) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = HintKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid HintKind", str)
+	}
+	return nil
+}
+
+func (e HintKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type HintError string
+
+const (
+	HintErrorGameOver        HintError = "GameOver"
+	HintErrorNoHintsLeft     HintError = "NoHintsLeft"
+	HintErrorNoHintAvailable HintError = "NoHintAvailable"
+)
+
+var AllHintError = []HintError{
+	HintErrorGameOver,
+	HintErrorNoHintsLeft,
+	HintErrorNoHintAvailable,
+}
+
+func (e HintError) IsValid() bool {
+	switch e {
+	case HintErrorGameOver, HintErrorNoHintsLeft, HintErrorNoHintAvailable:
+		return true
+	}
+	return false
+}
+
+func (e HintError) String() string {
+	return string(e)
+}
+
+func (e *HintError) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = HintError(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid HintError", str)
+	}
+	return nil
+}
+
+func (e HintError) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
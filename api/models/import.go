@@ -0,0 +1,61 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ImportResult is what happened to each game found in pasted share text
+type ImportResult struct {
+	Imported []*GameBoard    `json:"imported"`
+	Skipped  []SkippedImport `json:"skipped"`
+}
+
+type SkippedImport struct {
+	Day    int              `json:"day"`
+	Reason ImportSkipReason `json:"reason"`
+}
+
+type ImportSkipReason string
+
+const (
+	ImportSkipReasonInvalidGrid   ImportSkipReason = "InvalidGrid"
+	ImportSkipReasonNotInPast     ImportSkipReason = "NotInPast"
+	ImportSkipReasonAlreadyPlayed ImportSkipReason = "AlreadyPlayed"
+)
+
+var AllImportSkipReason = []ImportSkipReason{
+	ImportSkipReasonInvalidGrid,
+	ImportSkipReasonNotInPast,
+	ImportSkipReasonAlreadyPlayed,
+}
+
+func (e ImportSkipReason) IsValid() bool {
+	switch e {
+	case ImportSkipReasonInvalidGrid, ImportSkipReasonNotInPast, ImportSkipReasonAlreadyPlayed:
+		return true
+	}
+	return false
+}
+
+func (e ImportSkipReason) String() string {
+	return string(e)
+}
+
+func (e *ImportSkipReason) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportSkipReason(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportSkipReason", str)
+	}
+	return nil
+}
+
+func (e ImportSkipReason) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"time"
 )
 
 type LeaderboardRepo interface {
@@ -13,8 +14,17 @@ type LeaderboardRepo interface {
 	UpdateLeaderboardById(ctx context.Context, id string, leaderboard Leaderboard) error
 	FindLeaderBoardMembers(ctx context.Context, members []string) ([]*User, error)
 	FindLeaderboardStatsForMembers(ctx context.Context, members []string) (map[User][]UserStat, error)
+	FindMultiBoardStatsForMembers(ctx context.Context, members []string, variant MultiBoardVariant) (map[User][]MultiBoardUserStat, error)
 	FindLeaderboardsForUser(ctx context.Context, userId string) ([]*Leaderboard, error)
 	FindGameBoardsForUser(ctx context.Context, userId string) ([]*GameBoard, error)
+	FindUserStatistics(ctx context.Context, userId string) (*UserStatistics, error)
+	SaveUserStatistics(ctx context.Context, userId string, stats UserStatistics, previous *UserStatistics) error
+	ClearUserStatistics(ctx context.Context, userId string) error
+	FindAbsurdleBoardsForMembers(ctx context.Context, members []string) (map[User][]AbsurdleBoard, error)
+	FindPuzzle(ctx context.Context, id string) (*Puzzle, error)
+	FindPuzzlesForLeaderboard(ctx context.Context, joinId string) ([]*Puzzle, error)
+	AttachPuzzleToLeaderboard(ctx context.Context, puzzleId, joinId string) error
+	DeleteLeaderboard(ctx context.Context, joinId string) error
 }
 
 type Leaderboard struct {
@@ -22,20 +32,63 @@ type Leaderboard struct {
 	Name      string `json:"name"`
 	MemberIds []string
 	StoredId  string
-	Owner     string `json:"owner"`
+	Owner     string              `json:"owner"`
+	Settings  LeaderboardSettings `json:"settings"`
+}
+
+// LeaderboardSettings are chosen by a leaderboard's owner and decide how its members' games are
+// scored.
+type LeaderboardSettings struct {
+	HintsAllowed bool `json:"hintsAllowed"`
+	// HintPenalty is how many guesses each hint adds to a won game's score
+	HintPenalty int `json:"hintPenalty"`
+	// IncludeImported decides whether games imported from share text show up in stats. Owners have
+	// to opt in.
+	IncludeImported bool `json:"includeImported"`
+}
+
+func DefaultLeaderboardSettings() LeaderboardSettings {
+	return LeaderboardSettings{HintsAllowed: true, HintPenalty: 1}
+}
+
+type LeaderboardSettingsInput struct {
+	HintsAllowed    *bool `json:"hintsAllowed"`
+	HintPenalty     *int  `json:"hintPenalty"`
+	IncludeImported *bool `json:"includeImported"`
 }
 
 type LeaderboardStat struct {
-	Day     int        `json:"day"`
-	Stats   []UserStat `json:"stats"`
-	Visible bool       `json:"visible"`
+	Day        int        `json:"day"`
+	Stats      []UserStat `json:"stats"`
+	Visible    bool       `json:"visible"`
+	Difficulty *float64   `json:"difficulty"`
 }
 
 type UserStat struct {
-	Day     int            `json:"day"`
-	Guesses [][]GuessState `json:"guessCount"`
-	State   GameState      `json:"gameState"`
-	User    User           `json:"user"`
+	Day          int            `json:"day"`
+	Guesses      [][]GuessState `json:"guessCount"`
+	State        GameState      `json:"gameState"`
+	User         User           `json:"user"`
+	FirstGuessAt *time.Time     `json:"firstGuessAt"`
+	CompletedAt  *time.Time     `json:"completedAt"`
+	HintsUsed    int            `json:"hintsUsed"`
+	Imported     bool           `json:"imported"`
+}
+
+// SolveDuration is how many seconds it took from the first guess to finish the game
+func (u UserStat) SolveDuration() *int {
+	return solveSeconds(u.FirstGuessAt, u.CompletedAt)
+}
+
+type Standing struct {
+	Rank                int      `json:"rank"`
+	User                User     `json:"user"`
+	GamesPlayed         int      `json:"gamesPlayed"`
+	GamesWon            int      `json:"gamesWon"`
+	AverageGuesses      *float64 `json:"averageGuesses"`
+	AverageSolveSeconds *float64 `json:"averageSolveSeconds"`
+	FastestSolveSeconds *int     `json:"fastestSolveSeconds"`
+	SkillRating         *float64 `json:"skillRating"`
 }
 
 type LeaderboardResult interface {
@@ -57,6 +110,7 @@ const (
 	LeaderboardErrorMaxCapacity    LeaderboardError = "MaxCapacity"
 	LeaderboardErrorCouldNotCreate LeaderboardError = "CouldNotCreate"
 	LeaderboardErrorNotAuthorized  LeaderboardError = "NotAuthorized"
+	LeaderboardErrorInvalidInput   LeaderboardError = "InvalidInput"
 )
 
 var AllLeaderboardError = []LeaderboardError{
@@ -64,6 +118,7 @@ var AllLeaderboardError = []LeaderboardError{
 	LeaderboardErrorMaxCapacity,
 	LeaderboardErrorCouldNotCreate,
 	LeaderboardErrorNotAuthorized,
+	LeaderboardErrorInvalidInput,
 }
 
 func (e LeaderboardError) IsValid() bool {
@@ -71,7 +126,8 @@ func (e LeaderboardError) IsValid() bool {
 	case LeaderboardErrorDoesNotExist,
 		LeaderboardErrorMaxCapacity,
 		LeaderboardErrorCouldNotCreate,
-		LeaderboardErrorNotAuthorized:
+		LeaderboardErrorNotAuthorized,
+		LeaderboardErrorInvalidInput:
 		return true
 	}
 	return false
@@ -97,3 +153,46 @@ func (e *LeaderboardError) UnmarshalGQL(v interface{}) error {
 func (e LeaderboardError) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
+
+type LeaderboardRanking string
+
+const (
+	LeaderboardRankingGuesses LeaderboardRanking = "GUESSES"
+	LeaderboardRankingSpeed   LeaderboardRanking = "SPEED"
+	LeaderboardRankingSkill   LeaderboardRanking = "SKILL"
+)
+
+var AllLeaderboardRanking = []LeaderboardRanking{
+	LeaderboardRankingGuesses,
+	LeaderboardRankingSpeed,
+	LeaderboardRankingSkill,
+}
+
+func (e LeaderboardRanking) IsValid() bool {
+	switch e {
+	case LeaderboardRankingGuesses, LeaderboardRankingSpeed, LeaderboardRankingSkill:
+		return true
+	}
+	return false
+}
+
+func (e LeaderboardRanking) String() string {
+	return string(e)
+}
+
+func (e *LeaderboardRanking) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LeaderboardRanking(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LeaderboardRanking", str)
+	}
+	return nil
+}
+
+func (e LeaderboardRanking) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
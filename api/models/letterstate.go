@@ -0,0 +1,11 @@
+package models
+
+// LetterState is everything the guesses on a board have revealed about one letter
+type LetterState struct {
+	Letter            string      `json:"letter"`
+	State             LetterGuess `json:"state"`
+	CorrectPositions  []int       `json:"correctPositions"`
+	ExcludedPositions []int       `json:"excludedPositions"`
+	MinCount          int         `json:"minCount"`
+	MaxCount          *int        `json:"maxCount"`
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// MultiBoard is a daily game where every guess is played against several solutions at once
+type MultiBoard struct {
+	Day     int               `json:"day"`
+	Variant MultiBoardVariant `json:"variant"`
+	Boards  []SubBoard        `json:"boards"`
+	State   GameState         `json:"state"`
+}
+
+// GuessCount is the number of guesses played so far. A sub-board stops taking guesses once it is
+// solved, so the longest sub-board has every guess.
+func (m MultiBoard) GuessCount() int {
+	count := 0
+	for _, board := range m.Boards {
+		if len(board.Guesses) > count {
+			count = len(board.Guesses)
+		}
+	}
+	return count
+}
+
+func (m MultiBoard) MaxGuesses() int {
+	return m.Variant.MaxGuesses()
+}
+
+func (MultiBoard) IsGuessResult() {}
+
+type SubBoard struct {
+	Guesses [][]GuessState `json:"guesses"`
+	State   GameState      `json:"state"`
+}
+
+type MultiBoardUserStat struct {
+	Day        int        `json:"day"`
+	Boards     []SubBoard `json:"boards"`
+	State      GameState  `json:"state"`
+	GuessCount int        `json:"guessCount"`
+	User       User       `json:"user"`
+}
+
+type MultiBoardLeaderboardStat struct {
+	Day     int                  `json:"day"`
+	Stats   []MultiBoardUserStat `json:"stats"`
+	Visible bool                 `json:"visible"`
+}
+
+type MultiBoardVariant string
+
+const (
+	MultiBoardVariantDordle  MultiBoardVariant = "DORDLE"
+	MultiBoardVariantQuordle MultiBoardVariant = "QUORDLE"
+)
+
+var AllMultiBoardVariant = []MultiBoardVariant{
+	MultiBoardVariantDordle,
+	MultiBoardVariantQuordle,
+}
+
+// Boards is the number of simultaneous solutions for the variant
+func (e MultiBoardVariant) Boards() int {
+	if e == MultiBoardVariantQuordle {
+		return 4
+	}
+	return 2
+}
+
+// MaxGuesses allows five guesses on top of one per board, 7 for dordle and 9 for quordle
+func (e MultiBoardVariant) MaxGuesses() int {
+	return e.Boards() + 5
+}
+
+func (e MultiBoardVariant) IsValid() bool {
+	switch e {
+	case MultiBoardVariantDordle, MultiBoardVariantQuordle:
+		return true
+	}
+	return false
+}
+
+func (e MultiBoardVariant) String() string {
+	return string(e)
+}
+
+func (e *MultiBoardVariant) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MultiBoardVariant(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MultiBoardVariant", str)
+	}
+	return nil
+}
+
+func (e MultiBoardVariant) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,19 @@
+package models
+
+type PracticeBoard struct {
+	ID      string         `json:"id"`
+	UserId  string         `json:"-"`
+	Word    string         `json:"-"`
+	Guesses [][]GuessState `json:"guesses"`
+	State   GameState      `json:"state"`
+}
+
+// Solution reveals the practice word, but only once the game is over
+func (p PracticeBoard) Solution() *string {
+	if p.State == GameStateInProgress {
+		return nil
+	}
+	return &p.Word
+}
+
+func (PracticeBoard) IsGuessResult() {}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// UserPreferences are settings clients use to set up the game for a user
+type UserPreferences struct {
+	TimeZone        string       `json:"timeZone"`
+	HardModeDefault bool         `json:"hardModeDefault"`
+	Palette         SharePalette `json:"palette"`
+}
+
+func DefaultUserPreferences() UserPreferences {
+	return UserPreferences{TimeZone: "UTC", Palette: SharePaletteDefault}
+}
+
+// ProfileUpdate is a change to a user's profile. Only fields that are set are changed.
+type ProfileUpdate struct {
+	DisplayName *string
+	AvatarUrl   *string
+	Preferences *UserPreferences
+}
+
+type ProfileInput struct {
+	DisplayName *string           `json:"displayName"`
+	Preferences *PreferencesInput `json:"preferences"`
+}
+
+type PreferencesInput struct {
+	TimeZone        *string       `json:"timeZone"`
+	HardModeDefault *bool         `json:"hardModeDefault"`
+	Palette         *SharePalette `json:"palette"`
+}
+
+type ProfileResult interface {
+	IsProfileResult()
+}
+
+func (User) IsProfileResult() {}
+
+type ProfileResultError struct {
+	Error ProfileError `json:"error"`
+}
+
+func (ProfileResultError) IsProfileResult() {}
+
+type ProfileError string
+
+const (
+	ProfileErrorInvalidDisplayName       ProfileError = "InvalidDisplayName"
+	ProfileErrorInappropriateDisplayName ProfileError = "InappropriateDisplayName"
+	ProfileErrorInvalidTimeZone          ProfileError = "InvalidTimeZone"
+	ProfileErrorInvalidAvatar            ProfileError = "InvalidAvatar"
+)
+
+var AllProfileError = []ProfileError{
+	ProfileErrorInvalidDisplayName,
+	ProfileErrorInappropriateDisplayName,
+	ProfileErrorInvalidTimeZone,
+	ProfileErrorInvalidAvatar,
+}
+
+func (e ProfileError) IsValid() bool {
+	switch e {
+	case ProfileErrorInvalidDisplayName,
+		ProfileErrorInappropriateDisplayName,
+		ProfileErrorInvalidTimeZone,
+		ProfileErrorInvalidAvatar:
+		return true
+	}
+	return false
+}
+
+func (e ProfileError) String() string {
+	return string(e)
+}
+
+func (e *ProfileError) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProfileError(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProfileError", str)
+	}
+	return nil
+}
+
+func (e ProfileError) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Puzzle is a custom puzzle with a solution hand-picked by its creator. The solution is never
+// exposed through the API.
+type Puzzle struct {
+	ID           string    `json:"id"`
+	Creator      string    `json:"creator"`
+	Word         string    `json:"-"`
+	Leaderboards []string  `json:"leaderboards"`
+	CreatedAt    time.Time `json:"createdAt"`
+	Boards       []PuzzleBoard
+}
+
+func (Puzzle) IsPuzzleResult() {}
+
+// BoardForUser returns the user's board for this puzzle, or a fresh board if they haven't played yet
+func (p Puzzle) BoardForUser(userId string) PuzzleBoard {
+	for _, board := range p.Boards {
+		if board.UserId == userId {
+			return board
+		}
+	}
+	return PuzzleBoard{
+		PuzzleId: p.ID,
+		UserId:   userId,
+		Guesses:  make([][]GuessState, 0),
+		State:    GameStateInProgress,
+	}
+}
+
+type PuzzleBoard struct {
+	PuzzleId string         `json:"puzzleId"`
+	UserId   string         `json:"-"`
+	Guesses  [][]GuessState `json:"guesses"`
+	State    GameState      `json:"state"`
+}
+
+func (PuzzleBoard) IsGuessResult() {}
+
+type PuzzleStat struct {
+	User    User           `json:"user"`
+	Guesses [][]GuessState `json:"guesses"`
+	State   GameState      `json:"state"`
+}
+
+type PuzzleResult interface {
+	IsPuzzleResult()
+}
+
+func (InvalidGuess) IsPuzzleResult() {}
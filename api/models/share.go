@@ -0,0 +1,51 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// SharePalette is the set of squares share text is drawn with
+type SharePalette string
+
+const (
+	SharePaletteDefault      SharePalette = "DEFAULT"
+	SharePaletteLight        SharePalette = "LIGHT"
+	SharePaletteHighContrast SharePalette = "HIGH_CONTRAST"
+)
+
+var AllSharePalette = []SharePalette{
+	SharePaletteDefault,
+	SharePaletteLight,
+	SharePaletteHighContrast,
+}
+
+func (e SharePalette) IsValid() bool {
+	switch e {
+	case SharePaletteDefault, SharePaletteLight, SharePaletteHighContrast:
+		return true
+	}
+	return false
+}
+
+func (e SharePalette) String() string {
+	return string(e)
+}
+
+func (e *SharePalette) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SharePalette(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SharePalette", str)
+	}
+	return nil
+}
+
+func (e SharePalette) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"context"
+	"sort"
+)
+
+// GameResultRecorder is told about every daily game once it's finished
+type GameResultRecorder interface {
+	RecordGameResult(ctx context.Context, userId string, board GameBoard) error
+	RecomputeStatistics(ctx context.Context, userId string) (*UserStatistics, error)
+	ClearStatistics(ctx context.Context, userId string) error
+}
+
+// UserStatistics are a user's lifetime results for the daily board. They're maintained
+// incrementally as games finish, so reading them never has to scan every board.
+type UserStatistics struct {
+	GamesPlayed int `json:"gamesPlayed"`
+	GamesWon    int `json:"gamesWon"`
+	MaxStreak   int `json:"maxStreak"`
+
+	// CurrentStreak is only filled in when statistics are read, since a streak breaks as soon as a
+	// day is missed
+	CurrentStreak int `json:"currentStreak"`
+
+	// Streak is the streak as of LastWinDay
+	Streak        int `json:"-"`
+	LastWinDay    int `json:"-"`
+	LastPlayedDay int `json:"-"`
+
+	// Distribution counts wins by number of guesses, with losses in the last slot
+	Distribution    []int `json:"distribution"`
+	TotalWinGuesses int   `json:"-"`
+}
+
+const maxGuesses = 6
+
+func NewUserStatistics() UserStatistics {
+	return UserStatistics{
+		LastWinDay:    -1,
+		LastPlayedDay: -1,
+		Distribution:  make([]int, maxGuesses+1),
+	}
+}
+
+// ComputeUserStatistics builds statistics from scratch out of every board a user has played
+func ComputeUserStatistics(boards []*GameBoard) UserStatistics {
+	sorted := make([]*GameBoard, len(boards))
+	copy(sorted, boards)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Day < sorted[j].Day
+	})
+
+	stats := NewUserStatistics()
+	for _, board := range sorted {
+		if board.State != GameStateInProgress {
+			stats.Record(board.Day, board.State, len(board.Guesses))
+		}
+	}
+	return stats
+}
+
+// CanRecord is false if the day is not after every day already recorded, in which case statistics
+// have to be recomputed from the boards
+func (s UserStatistics) CanRecord(day int) bool {
+	return day > s.LastPlayedDay
+}
+
+// Record adds a finished game to the statistics. Days have to be recorded in order.
+func (s *UserStatistics) Record(day int, state GameState, guesses int) {
+	if len(s.Distribution) != maxGuesses+1 {
+		s.Distribution = make([]int, maxGuesses+1)
+	}
+
+	s.GamesPlayed += 1
+	s.LastPlayedDay = day
+	if state != GameStateWon {
+		s.Distribution[maxGuesses] += 1
+		s.Streak = 0
+		return
+	}
+
+	s.GamesWon += 1
+	s.TotalWinGuesses += guesses
+	if guesses >= 1 && guesses <= maxGuesses {
+		s.Distribution[guesses-1] += 1
+	}
+	if s.Streak > 0 && s.LastWinDay == day-1 {
+		s.Streak += 1
+	} else {
+		s.Streak = 1
+	}
+	s.LastWinDay = day
+	if s.Streak > s.MaxStreak {
+		s.MaxStreak = s.Streak
+	}
+}
+
+// StreakAsOf is the streak still alive on the given day. A streak survives until the day after the
+// last win is over, so today's unfinished board doesn't break it yet.
+func (s UserStatistics) StreakAsOf(today int) int {
+	if s.Streak > 0 && s.LastWinDay >= today-1 {
+		return s.Streak
+	}
+	return 0
+}
+
+func (s UserStatistics) WinPercentage() float64 {
+	if s.GamesPlayed == 0 {
+		return 0
+	}
+	return float64(s.GamesWon) / float64(s.GamesPlayed) * 100
+}
+
+func (s UserStatistics) AverageGuesses() *float64 {
+	if s.GamesWon == 0 {
+		return nil
+	}
+	average := float64(s.TotalWinGuesses) / float64(s.GamesWon)
+	return &average
+}
@@ -5,17 +5,62 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type UserRepo interface {
 	FindUserById(ctx context.Context, userId string) (*User, error)
 	FindUserByUuid(ctx context.Context, oauthUuid string) (*User, error)
-	InsertUser(ctx context.Context, user NewUser) (*User, error)
+	UpsertUser(ctx context.Context, user NewUser) (*User, bool, error)
+	FindDuplicateUsers(ctx context.Context) ([][]*User, error)
+	FindGameBoardsForUser(ctx context.Context, userId string) ([]*GameBoard, error)
+	ReplaceGameBoards(ctx context.Context, userId string, boards []GameBoard) error
+	DeleteUser(ctx context.Context, userId string) error
+	AddIdentities(ctx context.Context, userId string, uids []string) error
+	TransferIdentities(ctx context.Context, fromUserId, toUserId string) error
+	UpdateProfile(ctx context.Context, userId string, update ProfileUpdate) (*User, error)
+	TransferLeaderboards(ctx context.Context, fromUserId, toUserId string) error
+	FindIdentities(ctx context.Context, userId string) ([]string, error)
+	MarkUserDeleted(ctx context.Context, userId string, at time.Time) error
+	FindDeletedUsers(ctx context.Context) ([]*User, error)
+	RemoveUserFromLeaderboards(ctx context.Context, userId string) error
+	DeleteUserGames(ctx context.Context, userId string) error
+	ExportUserData(ctx context.Context, userId string) (*UserDataExport, error)
+	SetAdmin(ctx context.Context, userId string, admin bool) (*User, error)
+	SetBan(ctx context.Context, userId string, bannedAt *time.Time, reason *string) (*User, error)
+	MarkUserMerged(ctx context.Context, userId, intoUserId string) error
+	FindMergedUsers(ctx context.Context) ([]*User, error)
+	TransferMultiBoards(ctx context.Context, fromUserId, toUserId string) error
+	TransferAchievements(ctx context.Context, fromUserId, toUserId string) error
+	TransferUserGames(ctx context.Context, fromUserId, toUserId string) error
 }
 type User struct {
 	ID          string `json:"id"`
 	DisplayName string `json:"displayName"`
 	OauthId     string
+	AvatarUrl   *string         `json:"avatarUrl"`
+	Preferences UserPreferences `json:"preferences"`
+	DeletedAt   *time.Time      // set once the user has asked to be deleted, until they're gone
+	IsAdmin     bool            `json:"isAdmin"`
+	BannedAt    *time.Time      `json:"bannedAt"`
+	BanReason   *string         `json:"banReason"`
+	MergedInto  *string         // set while the user is being merged into another one, until they're gone
+}
+
+// GuestUidPrefix marks the uids of guests, who play without signing in
+const GuestUidPrefix = "guest:"
+
+func (u User) IsGuest() bool {
+	return strings.HasPrefix(u.OauthId, GuestUidPrefix)
+}
+
+func (u User) HasRole(role Role) bool {
+	switch role {
+	case RoleAdmin:
+		return u.IsAdmin
+	}
+	return false
 }
 
 type NewUserResult interface {
@@ -0,0 +1,135 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+type persistedAbsurdleBoard struct {
+	Id          primitive.ObjectID `bson:"_id,omitempty"`
+	AbsurdleId  string             `bson:"absurdle_id"`
+	UserId      primitive.ObjectID `bson:"user_id"`
+	Guesses     [][]guess          `bson:"guesses"`
+	State       models.GameState   `bson:"state"`
+	Remaining   int                `bson:"remaining"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	CompletedAt *time.Time         `bson:"completed_at,omitempty"`
+}
+
+func absurdleBoardModelToPersisted(board models.AbsurdleBoard) persistedAbsurdleBoard {
+	userOid, _ := primitive.ObjectIDFromHex(board.UserId)
+	return persistedAbsurdleBoard{
+		AbsurdleId:  board.ID,
+		UserId:      userOid,
+		Guesses:     guessesModelToPersisted(board.Guesses),
+		State:       board.State,
+		Remaining:   board.Remaining,
+		CreatedAt:   board.CreatedAt,
+		CompletedAt: board.CompletedAt,
+	}
+}
+
+func persistedAbsurdleBoardToModel(board persistedAbsurdleBoard) models.AbsurdleBoard {
+	return models.AbsurdleBoard{
+		ID:          board.AbsurdleId,
+		UserId:      board.UserId.Hex(),
+		Guesses:     persistedGuessesToModel(board.Guesses),
+		State:       board.State,
+		Remaining:   board.Remaining,
+		CreatedAt:   board.CreatedAt,
+		CompletedAt: board.CompletedAt,
+	}
+}
+
+func (s *Service) InsertAbsurdleBoard(ctx context.Context, board models.AbsurdleBoard) error {
+	collection := s.database.Collection("absurdle_boards")
+	_, insertErr := collection.InsertOne(ctx, absurdleBoardModelToPersisted(board))
+	if insertErr != nil {
+		return models.ErrRepoFailed{Message: insertErr.Error(), RepoMethod: "InsertAbsurdleBoard"}
+	}
+	return nil
+}
+
+func (s *Service) FindAbsurdleBoard(ctx context.Context, userId, id string) (*models.AbsurdleBoard, error) {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	collection := s.database.Collection("absurdle_boards")
+	result := collection.FindOne(ctx, bson.M{"absurdle_id": id, "user_id": userOid})
+	if result.Err() != nil {
+		if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+			return nil, models.ErrNotFound{Message: fmt.Sprintf("no absurdle board with id %s", id), RepoMethod: "FindAbsurdleBoard"}
+		}
+		return nil, models.ErrRepoFailed{Message: result.Err().Error(), RepoMethod: "FindAbsurdleBoard"}
+	}
+
+	board := new(persistedAbsurdleBoard)
+	decodeErr := result.Decode(board)
+	if decodeErr != nil {
+		return nil, models.ErrRepoFailed{Message: decodeErr.Error(), RepoMethod: "FindAbsurdleBoard"}
+	}
+
+	model := persistedAbsurdleBoardToModel(*board)
+	return &model, nil
+}
+
+func (s *Service) UpdateAbsurdleBoard(ctx context.Context, board models.AbsurdleBoard) error {
+	persist := absurdleBoardModelToPersisted(board)
+	collection := s.database.Collection("absurdle_boards")
+	filter := bson.M{"absurdle_id": persist.AbsurdleId, "user_id": persist.UserId}
+	update := bson.M{"$set": bson.M{
+		"guesses":      persist.Guesses,
+		"state":        persist.State,
+		"remaining":    persist.Remaining,
+		"completed_at": persist.CompletedAt,
+	}}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "UpdateAbsurdleBoard"}
+	} else if result.MatchedCount == 0 {
+		return models.ErrNotFound{Message: fmt.Sprintf("no absurdle board with id %s", board.ID), RepoMethod: "UpdateAbsurdleBoard"}
+	}
+	return nil
+}
+
+func (s *Service) FindAbsurdleBoardsForMembers(ctx context.Context, members []string) (map[models.User][]models.AbsurdleBoard, error) {
+	users, err := s.FindLeaderBoardMembers(ctx, members)
+	if err != nil {
+		return nil, err
+	}
+	oidToUser := make(map[primitive.ObjectID]models.User)
+	oids := bson.A{}
+	for _, user := range users {
+		oid, _ := primitive.ObjectIDFromHex(user.ID)
+		oidToUser[oid] = *user
+		oids = append(oids, oid)
+	}
+
+	collection := s.database.Collection("absurdle_boards")
+	results, err := collection.Find(ctx, bson.M{"user_id": bson.M{"$in": oids}})
+	if err != nil {
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "FindAbsurdleBoardsForMembers"}
+	}
+
+	boards := make(map[models.User][]models.AbsurdleBoard)
+	for _, user := range oidToUser {
+		boards[user] = make([]models.AbsurdleBoard, 0)
+	}
+	for results.Next(ctx) {
+		board := new(persistedAbsurdleBoard)
+		if decodeErr := results.Decode(board); decodeErr != nil {
+			return nil, models.ErrRepoFailed{Message: decodeErr.Error(), RepoMethod: "FindAbsurdleBoardsForMembers"}
+		}
+		user := oidToUser[board.UserId]
+		boards[user] = append(boards[user], persistedAbsurdleBoardToModel(*board))
+	}
+	if results.Err() != nil {
+		return nil, models.ErrRepoFailed{Message: results.Err().Error(), RepoMethod: "FindAbsurdleBoardsForMembers"}
+	}
+	return boards, nil
+}
@@ -0,0 +1,142 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type persistedAccessToken struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty"`
+	UserId     primitive.ObjectID  `bson:"user_id"`
+	Name       string              `bson:"name"`
+	Hash       string              `bson:"token_hash"`
+	Scopes     []models.TokenScope `bson:"scopes"`
+	CreatedAt  time.Time           `bson:"created_at"`
+	LastUsedAt *time.Time          `bson:"last_used_at,omitempty"`
+	RevokedAt  *time.Time          `bson:"revoked_at,omitempty"`
+}
+
+func persistedAccessTokenToModel(token persistedAccessToken) models.AccessToken {
+	scopes := token.Scopes
+	if scopes == nil {
+		scopes = make([]models.TokenScope, 0)
+	}
+	return models.AccessToken{
+		ID:         token.ID.Hex(),
+		UserId:     token.UserId.Hex(),
+		Name:       token.Name,
+		Hash:       token.Hash,
+		Scopes:     scopes,
+		CreatedAt:  token.CreatedAt,
+		LastUsedAt: token.LastUsedAt,
+		RevokedAt:  token.RevokedAt,
+	}
+}
+
+func (s *Service) InsertAccessToken(ctx context.Context, token models.AccessToken) (*models.AccessToken, error) {
+	userOid, _ := primitive.ObjectIDFromHex(token.UserId)
+	persist := persistedAccessToken{
+		UserId:    userOid,
+		Name:      token.Name,
+		Hash:      token.Hash,
+		Scopes:    token.Scopes,
+		CreatedAt: token.CreatedAt,
+	}
+	result, err := s.database.Collection("access_tokens").InsertOne(ctx, persist)
+	if err != nil {
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "InsertAccessToken"}
+	}
+
+	persist.ID = result.InsertedID.(primitive.ObjectID)
+	model := persistedAccessTokenToModel(persist)
+	return &model, nil
+}
+
+func (s *Service) FindAccessTokenByHash(ctx context.Context, hash string) (*models.AccessToken, error) {
+	result := s.database.Collection("access_tokens").FindOne(ctx, bson.M{"token_hash": hash})
+	if result.Err() != nil {
+		if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+			return nil, models.ErrNotFound{Message: "no matching access token", RepoMethod: "FindAccessTokenByHash"}
+		}
+		return nil, models.ErrRepoFailed{Message: result.Err().Error(), RepoMethod: "FindAccessTokenByHash"}
+	}
+
+	token := new(persistedAccessToken)
+	if err := result.Decode(token); err != nil {
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "FindAccessTokenByHash"}
+	}
+	model := persistedAccessTokenToModel(*token)
+	return &model, nil
+}
+
+func (s *Service) FindAccessTokensForUser(ctx context.Context, userId string) ([]*models.AccessToken, error) {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	opts := options.Find().SetSort(bson.M{"created_at": -1})
+	results, err := s.database.Collection("access_tokens").Find(ctx, bson.M{"user_id": userOid}, opts)
+	if err != nil {
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "FindAccessTokensForUser"}
+	}
+
+	tokens := make([]*models.AccessToken, 0)
+	for results.Next(ctx) {
+		token := new(persistedAccessToken)
+		if decodeErr := results.Decode(token); decodeErr != nil {
+			return nil, models.ErrRepoFailed{Message: decodeErr.Error(), RepoMethod: "FindAccessTokensForUser"}
+		}
+		model := persistedAccessTokenToModel(*token)
+		tokens = append(tokens, &model)
+	}
+	if results.Err() != nil {
+		return nil, models.ErrRepoFailed{Message: results.Err().Error(), RepoMethod: "FindAccessTokensForUser"}
+	}
+	return tokens, nil
+}
+
+func (s *Service) TouchAccessToken(ctx context.Context, id string, at time.Time) error {
+	tokenOid, _ := primitive.ObjectIDFromHex(id)
+	update := bson.M{"$max": bson.M{"last_used_at": at}}
+	if _, err := s.database.Collection("access_tokens").UpdateOne(ctx, bson.M{"_id": tokenOid}, update); err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "TouchAccessToken"}
+	}
+	return nil
+}
+
+// RevokeAccessToken revokes one of a user's tokens. Revoking a token again keeps the first time.
+func (s *Service) RevokeAccessToken(ctx context.Context, userId, id string, at time.Time) error {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	tokenOid, _ := primitive.ObjectIDFromHex(id)
+	filter := bson.M{"_id": tokenOid, "user_id": userOid}
+	result, err := s.database.Collection("access_tokens").UpdateOne(ctx, filter, bson.M{"$min": bson.M{"revoked_at": at}})
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "RevokeAccessToken"}
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrNotFound{Message: fmt.Sprintf("no access token with id %s", id), RepoMethod: "RevokeAccessToken"}
+	}
+	return nil
+}
+
+func (s *Service) DeleteAccessTokensForUser(ctx context.Context, userId string) error {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	if _, err := s.database.Collection("access_tokens").DeleteMany(ctx, bson.M{"user_id": userOid}); err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "DeleteAccessTokensForUser"}
+	}
+	return nil
+}
+
+func (s *Service) TransferAccessTokens(ctx context.Context, fromUserId, toUserId string) error {
+	fromOid, _ := primitive.ObjectIDFromHex(fromUserId)
+	toOid, _ := primitive.ObjectIDFromHex(toUserId)
+	update := bson.M{"$set": bson.M{"user_id": toOid}}
+	if _, err := s.database.Collection("access_tokens").UpdateMany(ctx, bson.M{"user_id": fromOid}, update); err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "TransferAccessTokens"}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+type persistedAccount struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	UID          string             `bson:"uid"`
+	Email        string             `bson:"email"`
+	DisplayName  string             `bson:"display_name"`
+	PasswordHash string             `bson:"password_hash,omitempty"`
+	CreatedAt    time.Time          `bson:"created_at"`
+
+	SessionsNotBefore *time.Time `bson:"sessions_not_before,omitempty"`
+}
+
+type persistedLoginToken struct {
+	Hash         string                   `bson:"token_hash"`
+	AccountUid   string                   `bson:"account_uid,omitempty"`
+	Purpose      models.LoginTokenPurpose `bson:"purpose"`
+	ExpiresAt    time.Time                `bson:"expires_at"`
+	Email        string                   `bson:"email,omitempty"`
+	DisplayName  string                   `bson:"display_name,omitempty"`
+	PasswordHash string                   `bson:"password_hash,omitempty"`
+}
+
+func persistedAccountToModel(account persistedAccount) models.Account {
+	return models.Account{
+		ID:           account.ID.Hex(),
+		UID:          account.UID,
+		Email:        account.Email,
+		DisplayName:  account.DisplayName,
+		PasswordHash: account.PasswordHash,
+		CreatedAt:    account.CreatedAt,
+
+		SessionsNotBefore: account.SessionsNotBefore,
+	}
+}
+
+func (s *Service) InsertAccount(ctx context.Context, account models.Account) (*models.Account, error) {
+	collection := s.database.Collection("accounts")
+	persist := persistedAccount{
+		UID:          account.UID,
+		Email:        account.Email,
+		DisplayName:  account.DisplayName,
+		PasswordHash: account.PasswordHash,
+		CreatedAt:    account.CreatedAt,
+	}
+	result, err := collection.InsertOne(ctx, persist)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, models.ErrAlreadyExists{Message: "an account already uses this email", RepoMethod: "InsertAccount"}
+		}
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "InsertAccount"}
+	}
+
+	persist.ID = result.InsertedID.(primitive.ObjectID)
+	model := persistedAccountToModel(persist)
+	return &model, nil
+}
+
+func (s *Service) findAccount(ctx context.Context, filter bson.M, repoMethod string) (*models.Account, error) {
+	result := s.database.Collection("accounts").FindOne(ctx, filter)
+	if result.Err() != nil {
+		if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+			return nil, models.ErrNotFound{Message: "no matching account", RepoMethod: repoMethod}
+		}
+		return nil, models.ErrRepoFailed{Message: result.Err().Error(), RepoMethod: repoMethod}
+	}
+
+	account := new(persistedAccount)
+	if err := result.Decode(account); err != nil {
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: repoMethod}
+	}
+	model := persistedAccountToModel(*account)
+	return &model, nil
+}
+
+func (s *Service) FindAccountByEmail(ctx context.Context, email string) (*models.Account, error) {
+	return s.findAccount(ctx, bson.M{"email": email}, "FindAccountByEmail")
+}
+
+func (s *Service) FindAccountByUid(ctx context.Context, uid string) (*models.Account, error) {
+	return s.findAccount(ctx, bson.M{"uid": uid}, "FindAccountByUid")
+}
+
+// UpdateAccountPassword sets an account's password, and signs out every session issued before
+// sessionsNotBefore
+func (s *Service) UpdateAccountPassword(ctx context.Context, uid, passwordHash string, sessionsNotBefore time.Time) error {
+	collection := s.database.Collection("accounts")
+	update := bson.M{"$set": bson.M{"password_hash": passwordHash, "sessions_not_before": sessionsNotBefore}}
+	result, err := collection.UpdateOne(ctx, bson.M{"uid": uid}, update)
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "UpdateAccountPassword"}
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrNotFound{Message: fmt.Sprintf("no account with uid %s", uid), RepoMethod: "UpdateAccountPassword"}
+	}
+	return nil
+}
+
+func (s *Service) InsertLoginToken(ctx context.Context, token models.LoginToken) error {
+	collection := s.database.Collection("login_tokens")
+	_, err := collection.InsertOne(ctx, persistedLoginToken{
+		Hash:         token.Hash,
+		AccountUid:   token.AccountUid,
+		Purpose:      token.Purpose,
+		ExpiresAt:    token.ExpiresAt,
+		Email:        token.Email,
+		DisplayName:  token.DisplayName,
+		PasswordHash: token.PasswordHash,
+	})
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "InsertLoginToken"}
+	}
+	return nil
+}
+
+// ConsumeLoginToken deletes a token as it's found, so it can only be used once. Expired tokens are
+// reported as ErrNotFound, even before the TTL index gets around to removing them.
+func (s *Service) ConsumeLoginToken(ctx context.Context, tokenHash string, purpose models.LoginTokenPurpose, now time.Time) (*models.LoginToken, error) {
+	collection := s.database.Collection("login_tokens")
+	filter := bson.M{"token_hash": tokenHash, "purpose": purpose, "expires_at": bson.M{"$gt": now}}
+	result := collection.FindOneAndDelete(ctx, filter)
+	if result.Err() != nil {
+		if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+			return nil, models.ErrNotFound{Message: "no usable login token", RepoMethod: "ConsumeLoginToken"}
+		}
+		return nil, models.ErrRepoFailed{Message: result.Err().Error(), RepoMethod: "ConsumeLoginToken"}
+	}
+
+	token := new(persistedLoginToken)
+	if err := result.Decode(token); err != nil {
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "ConsumeLoginToken"}
+	}
+	return &models.LoginToken{
+		Hash:         token.Hash,
+		AccountUid:   token.AccountUid,
+		Purpose:      token.Purpose,
+		ExpiresAt:    token.ExpiresAt,
+		Email:        token.Email,
+		DisplayName:  token.DisplayName,
+		PasswordHash: token.PasswordHash,
+	}, nil
+}
+
+// DeleteAccounts deletes the accounts with any of the uids, along with their login tokens
+func (s *Service) DeleteAccounts(ctx context.Context, uids []string) error {
+	filter := bson.M{"account_uid": bson.M{"$in": uids}}
+	if _, err := s.database.Collection("login_tokens").DeleteMany(ctx, filter); err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "DeleteAccounts"}
+	}
+	if _, err := s.database.Collection("accounts").DeleteMany(ctx, bson.M{"uid": bson.M{"$in": uids}}); err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "DeleteAccounts"}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"github.com/amanzanero/wordleboard/api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type persistedAchievement struct {
+	Kind       models.AchievementKind `bson:"kind"`
+	UnlockedAt time.Time              `bson:"unlocked_at"`
+}
+
+func (s *Service) FindAchievements(ctx context.Context, userId string) ([]models.Achievement, error) {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	opt := options.FindOne().SetProjection(bson.M{"achievements": 1})
+
+	collection := s.database.Collection("users")
+	doc := collection.FindOne(ctx, bson.M{"_id": userOid}, opt)
+	if doc.Err() != nil {
+		if errors.Is(doc.Err(), mongo.ErrNoDocuments) {
+			return nil, models.ErrNotFound{Message: doc.Err().Error(), RepoMethod: "FindAchievements"}
+		}
+		return nil, models.ErrRepoFailed{Message: doc.Err().Error(), RepoMethod: "FindAchievements"}
+	}
+
+	user := new(struct {
+		Achievements []persistedAchievement `bson:"achievements"`
+	})
+	if decodeErr := doc.Decode(user); decodeErr != nil {
+		return nil, models.ErrRepoFailed{Message: decodeErr.Error(), RepoMethod: "FindAchievements"}
+	}
+
+	achievements := make([]models.Achievement, len(user.Achievements))
+	for i, achievement := range user.Achievements {
+		achievements[i] = models.Achievement{
+			Kind:       achievement.Kind,
+			UnlockedAt: achievement.UnlockedAt,
+		}
+	}
+	return achievements, nil
+}
+
+// UnlockAchievement adds an achievement unless the user already has one of the same kind, returning
+// whether it was added
+func (s *Service) UnlockAchievement(ctx context.Context, userId string, achievement models.Achievement) (bool, error) {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	persist := persistedAchievement{
+		Kind:       achievement.Kind,
+		UnlockedAt: achievement.UnlockedAt,
+	}
+
+	collection := s.database.Collection("users")
+	filter := bson.M{"_id": userOid, "achievements.kind": bson.M{"$ne": achievement.Kind}}
+	result, err := collection.UpdateOne(ctx, filter, bson.M{"$push": bson.M{"achievements": persist}})
+	if err != nil {
+		return false, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "UnlockAchievement"}
+	}
+	return result.ModifiedCount == 1, nil
+}
@@ -0,0 +1,156 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// MarkUserDeleted leaves a tombstone on a user, so a deletion that stops part way can be found and
+// finished. Marking a user again keeps the first time.
+func (s *Service) MarkUserDeleted(ctx context.Context, userId string, at time.Time) error {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	result, err := s.database.Collection("users").UpdateOne(ctx, bson.M{"_id": userOid}, bson.M{"$min": bson.M{"deleted_at": at}})
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "MarkUserDeleted"}
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrNotFound{Message: fmt.Sprintf("no user with id %s", userId), RepoMethod: "MarkUserDeleted"}
+	}
+	return nil
+}
+
+func (s *Service) FindDeletedUsers(ctx context.Context) ([]*models.User, error) {
+	opts := options.Find().SetProjection(bson.M{"game_boards": 0, "multi_boards": 0})
+	results, err := s.database.Collection("users").Find(ctx, bson.M{"deleted_at": bson.M{"$exists": true}}, opts)
+	if err != nil {
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "FindDeletedUsers"}
+	}
+
+	found := make([]*models.User, 0)
+	for results.Next(ctx) {
+		user := new(persistedUser)
+		if decodeErr := results.Decode(user); decodeErr != nil {
+			return nil, models.ErrRepoFailed{Message: decodeErr.Error(), RepoMethod: "FindDeletedUsers"}
+		}
+		model := persistedUserToModel(*user)
+		found = append(found, &model)
+	}
+	if results.Err() != nil {
+		return nil, models.ErrRepoFailed{Message: results.Err().Error(), RepoMethod: "FindDeletedUsers"}
+	}
+	return found, nil
+}
+
+// RemoveUserFromLeaderboards takes a user out of every leaderboard they're in. Leaderboards they
+// own are handed to the member that joined after them, or deleted if nobody else is in them.
+func (s *Service) RemoveUserFromLeaderboards(ctx context.Context, userId string) error {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	collection := s.database.Collection("leaderboards")
+
+	results, err := collection.Find(ctx, bson.M{"owner_id": userOid})
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "RemoveUserFromLeaderboards"}
+	}
+	owned := make([]persistLeaderboard, 0)
+	for results.Next(ctx) {
+		lb := new(persistLeaderboard)
+		if decodeErr := results.Decode(lb); decodeErr != nil {
+			return models.ErrRepoFailed{Message: decodeErr.Error(), RepoMethod: "RemoveUserFromLeaderboards"}
+		}
+		owned = append(owned, *lb)
+	}
+	if results.Err() != nil {
+		return models.ErrRepoFailed{Message: results.Err().Error(), RepoMethod: "RemoveUserFromLeaderboards"}
+	}
+
+	for _, lb := range owned {
+		if err := s.handOffLeaderboard(ctx, lb, userOid); err != nil {
+			return err
+		}
+	}
+
+	_, err = collection.UpdateMany(ctx, bson.M{"member_ids": userOid}, bson.M{"$pull": bson.M{"member_ids": userOid}})
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "RemoveUserFromLeaderboards"}
+	}
+	return nil
+}
+
+func (s *Service) handOffLeaderboard(ctx context.Context, lb persistLeaderboard, ownerOid primitive.ObjectID) error {
+	for _, member := range lb.Members {
+		if member == ownerOid {
+			continue
+		}
+		update := bson.M{"$set": bson.M{"owner_id": member}}
+		if _, err := s.database.Collection("leaderboards").UpdateOne(ctx, bson.M{"_id": lb.Id}, update); err != nil {
+			return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "RemoveUserFromLeaderboards"}
+		}
+		return nil
+	}
+	return s.deleteLeaderboard(ctx, lb, "RemoveUserFromLeaderboards")
+}
+
+func (s *Service) DeleteLeaderboard(ctx context.Context, joinId string) error {
+	result := s.database.Collection("leaderboards").FindOne(ctx, bson.M{"join_id": joinId})
+	if result.Err() != nil {
+		if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+			return models.ErrNotFound{Message: fmt.Sprintf("no leaderboard with id %s", joinId), RepoMethod: "DeleteLeaderboard"}
+		}
+		return models.ErrRepoFailed{Message: result.Err().Error(), RepoMethod: "DeleteLeaderboard"}
+	}
+
+	lb := new(persistLeaderboard)
+	if err := result.Decode(lb); err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "DeleteLeaderboard"}
+	}
+	return s.deleteLeaderboard(ctx, *lb, "DeleteLeaderboard")
+}
+
+func (s *Service) deleteLeaderboard(ctx context.Context, lb persistLeaderboard, repoMethod string) error {
+	// puzzles are detached first, so they aren't left pointing at a missing leaderboard
+	_, err := s.database.Collection("puzzles").UpdateMany(
+		ctx,
+		bson.M{"leaderboard_ids": lb.JoinId},
+		bson.M{"$pull": bson.M{"leaderboard_ids": lb.JoinId}},
+	)
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: repoMethod}
+	}
+	if _, err := s.database.Collection("leaderboards").DeleteOne(ctx, bson.M{"_id": lb.Id}); err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: repoMethod}
+	}
+	return nil
+}
+
+// DeleteUserGames deletes the games a user played outside of their user document: practice games,
+// absurdle games, puzzles they created, and their boards on other puzzles
+func (s *Service) DeleteUserGames(ctx context.Context, userId string) error {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	if _, err := s.database.Collection("practice_boards").DeleteMany(ctx, bson.M{"user_id": userOid}); err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "DeleteUserGames"}
+	}
+	if _, err := s.database.Collection("absurdle_boards").DeleteMany(ctx, bson.M{"user_id": userOid}); err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "DeleteUserGames"}
+	}
+
+	puzzles := s.database.Collection("puzzles")
+	if _, err := puzzles.DeleteMany(ctx, bson.M{"creator_id": userOid}); err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "DeleteUserGames"}
+	}
+	_, err := puzzles.UpdateMany(
+		ctx,
+		bson.M{"boards.user_id": userOid},
+		bson.M{"$pull": bson.M{"boards": bson.M{"user_id": userOid}}},
+	)
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "DeleteUserGames"}
+	}
+	return nil
+}
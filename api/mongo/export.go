@@ -0,0 +1,98 @@
+package mongo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// ExportUserData collects the documents stored about a user as relaxed extended JSON, so nothing
+// is lost converting them to models
+func (s *Service) ExportUserData(ctx context.Context, userId string) (*models.UserDataExport, error) {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	result := s.database.Collection("users").FindOne(ctx, bson.M{"_id": userOid})
+	if result.Err() != nil {
+		if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+			return nil, models.ErrNotFound{Message: fmt.Sprintf("no user with id %s", userId), RepoMethod: "ExportUserData"}
+		}
+		return nil, models.ErrRepoFailed{Message: result.Err().Error(), RepoMethod: "ExportUserData"}
+	}
+	userDoc, rawErr := result.DecodeBytes()
+	if rawErr != nil {
+		return nil, models.ErrRepoFailed{Message: rawErr.Error(), RepoMethod: "ExportUserData"}
+	}
+	user, jsonErr := bson.MarshalExtJSON(userDoc, false, false)
+	if jsonErr != nil {
+		return nil, models.ErrRepoFailed{Message: jsonErr.Error(), RepoMethod: "ExportUserData"}
+	}
+
+	uids, uidsErr := s.FindIdentities(ctx, userId)
+	if uidsErr != nil {
+		return nil, uidsErr
+	}
+
+	export := &models.UserDataExport{ExportedAt: time.Now(), User: user}
+	var err error
+	export.Accounts, err = s.exportDocuments(ctx, "accounts", bson.M{"uid": bson.M{"$in": uids}}, bson.M{"password_hash": 0})
+	if err != nil {
+		return nil, err
+	}
+	// other members are left out, they're not this user's data
+	export.AccessTokens, err = s.exportDocuments(ctx, "access_tokens", bson.M{"user_id": userOid}, bson.M{"token_hash": 0})
+	if err != nil {
+		return nil, err
+	}
+	export.Leaderboards, err = s.exportDocuments(ctx, "leaderboards", bson.M{"member_ids": userOid}, bson.M{"member_ids": 0})
+	if err != nil {
+		return nil, err
+	}
+	export.PracticeBoards, err = s.exportDocuments(ctx, "practice_boards", bson.M{"user_id": userOid}, nil)
+	if err != nil {
+		return nil, err
+	}
+	export.AbsurdleBoards, err = s.exportDocuments(ctx, "absurdle_boards", bson.M{"user_id": userOid}, nil)
+	if err != nil {
+		return nil, err
+	}
+	export.Puzzles, err = s.exportDocuments(ctx, "puzzles", bson.M{"creator_id": userOid}, bson.M{"boards": 0})
+	if err != nil {
+		return nil, err
+	}
+	// a user only ever has one board on a puzzle, so the positional projection finds all of them
+	export.PuzzleBoards, err = s.exportDocuments(ctx, "puzzles", bson.M{"boards.user_id": userOid}, bson.M{"puzzle_id": 1, "boards.$": 1})
+	if err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+func (s *Service) exportDocuments(ctx context.Context, collection string, filter, projection bson.M) ([]json.RawMessage, error) {
+	opts := options.Find()
+	if projection != nil {
+		opts.SetProjection(projection)
+	}
+	results, err := s.database.Collection(collection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "ExportUserData"}
+	}
+
+	docs := make([]json.RawMessage, 0)
+	for results.Next(ctx) {
+		doc, jsonErr := bson.MarshalExtJSON(results.Current, false, false)
+		if jsonErr != nil {
+			return nil, models.ErrRepoFailed{Message: jsonErr.Error(), RepoMethod: "ExportUserData"}
+		}
+		docs = append(docs, doc)
+	}
+	if results.Err() != nil {
+		return nil, models.ErrRepoFailed{Message: results.Err().Error(), RepoMethod: "ExportUserData"}
+	}
+	return docs, nil
+}
@@ -10,12 +10,40 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
 )
 
 type persistedGameBoard struct {
-	Day     int              `bson:"day"`
-	Guesses [][]guess        `bson:"guesses"`
-	State   models.GameState `bson:"state"`
+	Day          int              `bson:"day"`
+	Guesses      [][]guess        `bson:"guesses"`
+	State        models.GameState `bson:"state"`
+	CreatedAt    time.Time        `bson:"created_at"`
+	FirstGuessAt *time.Time       `bson:"first_guess_at,omitempty"`
+	GuessTimes   []time.Time      `bson:"guess_times"`
+	CompletedAt  *time.Time       `bson:"completed_at,omitempty"`
+	Hints        []persistedHint  `bson:"hints"`
+	Imported     bool             `bson:"imported,omitempty"`
+	HardMode     bool             `bson:"hard_mode,omitempty"`
+}
+
+type persistedHint struct {
+	Kind        models.HintKind `bson:"kind"`
+	Letter      string          `bson:"letter"`
+	Position    *int            `bson:"position,omitempty"`
+	RequestedAt time.Time       `bson:"requested_at"`
+}
+
+func persistedHintsToModel(persistedHints []persistedHint) []models.Hint {
+	hints := make([]models.Hint, len(persistedHints))
+	for i, hint := range persistedHints {
+		hints[i] = models.Hint{
+			Kind:        hint.Kind,
+			Letter:      hint.Letter,
+			Position:    hint.Position,
+			RequestedAt: hint.RequestedAt,
+		}
+	}
+	return hints
 }
 
 type guess struct {
@@ -70,18 +98,28 @@ func (s *Service) FindGameBoardByUserAndDay(ctx context.Context, userId string,
 	if len(user.GameBoards) == 0 {
 		return nil, models.ErrNotFound{RepoMethod: "FindGameBoardByUserAndDay", Message: "no gameboards found for user"}
 	}
-	board := user.GameBoards[0]
+	board := persistedGameBoardToModel(user.GameBoards[0])
+	return &board, nil
+}
 
-	return &models.GameBoard{
-		Day:     board.Day,
-		Guesses: persistedGuessesToModel(board.Guesses),
-		State:   board.State,
-	}, nil
+func persistedGameBoardToModel(board persistedGameBoard) models.GameBoard {
+	return models.GameBoard{
+		Day:          board.Day,
+		Guesses:      persistedGuessesToModel(board.Guesses),
+		State:        board.State,
+		CreatedAt:    board.CreatedAt,
+		FirstGuessAt: board.FirstGuessAt,
+		GuessTimes:   board.GuessTimes,
+		CompletedAt:  board.CompletedAt,
+		Hints:        persistedHintsToModel(board.Hints),
+		Imported:     board.Imported,
+		HardMode:     board.HardMode,
+	}
 }
 
-func gameBoardModelToPersistedModel(gb models.GameBoard) persistedGameBoard {
-	guesses := make([][]guess, len(gb.Guesses))
-	for i, guessRow := range gb.Guesses {
+func guessesModelToPersisted(modelGuesses [][]models.GuessState) [][]guess {
+	guesses := make([][]guess, len(modelGuesses))
+	for i, guessRow := range modelGuesses {
 		row := make([]guess, len(guessRow))
 		for j, g := range guessRow {
 			row[j] = guess{
@@ -91,10 +129,30 @@ func gameBoardModelToPersistedModel(gb models.GameBoard) persistedGameBoard {
 		}
 		guesses[i] = row
 	}
+	return guesses
+}
+
+func gameBoardModelToPersistedModel(gb models.GameBoard) persistedGameBoard {
+	hints := make([]persistedHint, len(gb.Hints))
+	for i, hint := range gb.Hints {
+		hints[i] = persistedHint{
+			Kind:        hint.Kind,
+			Letter:      hint.Letter,
+			Position:    hint.Position,
+			RequestedAt: hint.RequestedAt,
+		}
+	}
 	return persistedGameBoard{
-		Day:     gb.Day,
-		Guesses: guesses,
-		State:   gb.State,
+		Day:          gb.Day,
+		Guesses:      guessesModelToPersisted(gb.Guesses),
+		State:        gb.State,
+		CreatedAt:    gb.CreatedAt,
+		FirstGuessAt: gb.FirstGuessAt,
+		GuessTimes:   gb.GuessTimes,
+		CompletedAt:  gb.CompletedAt,
+		Hints:        hints,
+		Imported:     gb.Imported,
+		HardMode:     gb.HardMode,
 	}
 }
 
@@ -106,8 +164,8 @@ func (s *Service) InsertGameBoard(ctx context.Context, userId string, gameBoard
 	filter := bson.M{"_id": userOid}
 	update := bson.M{"$push": bson.M{
 		"game_boards": bson.D{
-			{"$each", bson.A{persist}},
-			{"$sort", bson.M{"day": 1}},
+			{Key: "$each", Value: bson.A{persist}},
+			{Key: "$sort", Value: bson.M{"day": 1}},
 		},
 	}}
 	result, err := collection.UpdateOne(ctx, filter, update)
@@ -122,23 +180,102 @@ func (s *Service) InsertGameBoard(ctx context.Context, userId string, gameBoard
 	return nil
 }
 
-func (s *Service) UpdateGameBoardByUserAndDay(ctx context.Context, day int, userId string, gameBoard models.GameBoard) error {
-	userOid, _ := primitive.ObjectIDFromHex(userId)
+// InsertGameBoardIfMissing inserts a board unless the user already has one for its day, which is
+// reported as ErrNotFound
+func (s *Service) InsertGameBoardIfMissing(ctx context.Context, userId string, gameBoard models.GameBoard) error {
 	persist := gameBoardModelToPersistedModel(gameBoard)
+	userOid, _ := primitive.ObjectIDFromHex(userId)
 
 	collection := s.database.Collection("users")
-	filter := bson.M{"_id": userOid, "game_boards.day": day}
-	update := bson.M{"$set": bson.M{
-		"game_boards.$": persist,
+	filter := bson.M{"_id": userOid, "game_boards.day": bson.M{"$ne": gameBoard.Day}}
+	update := bson.M{"$push": bson.M{
+		"game_boards": bson.D{
+			{Key: "$each", Value: bson.A{persist}},
+			{Key: "$sort", Value: bson.M{"day": 1}},
+		},
 	}}
 	result, err := collection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		return models.ErrRepoFailed{
-			Message:    err.Error(),
-			RepoMethod: "UpdateGameBoardByUserAndDay",
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "InsertGameBoardIfMissing"}
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrNotFound{
+			Message:    fmt.Sprintf("user %s already has a board for day %d", userId, gameBoard.Day),
+			RepoMethod: "InsertGameBoardIfMissing",
 		}
-	} else if result.MatchedCount == 0 {
-		return models.ErrNotFound{RepoMethod: "UpdateGameBoardByUserAndDay", Message: "did not update any documents"}
 	}
 	return nil
 }
+
+// AddGameBoardGuess saves the last guess of a board, along with the state and timestamps it changed.
+// It's only saved if the board still has the guesses before it, otherwise it's reported as
+// ErrConflict, so a guess and a hint made at the same time don't overwrite each other.
+func (s *Service) AddGameBoardGuess(ctx context.Context, userId string, gameBoard models.GameBoard) error {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	persist := gameBoardModelToPersistedModel(gameBoard)
+	previous := len(persist.Guesses) - 1
+	if previous < 0 || len(persist.GuessTimes) == 0 {
+		return models.ErrRepoFailed{Message: "the board has no guess to add", RepoMethod: "AddGameBoardGuess"}
+	}
+
+	board := bson.M{"day": gameBoard.Day, "state": models.GameStateInProgress}
+	matchLength(board, "guesses", previous)
+	filter := bson.M{"_id": userOid, "game_boards": bson.M{"$elemMatch": board}}
+	set := bson.M{"game_boards.$.state": persist.State}
+	if persist.FirstGuessAt != nil {
+		set["game_boards.$.first_guess_at"] = persist.FirstGuessAt
+	}
+	if persist.CompletedAt != nil {
+		set["game_boards.$.completed_at"] = persist.CompletedAt
+	}
+	update := bson.M{
+		"$push": bson.M{
+			"game_boards.$.guesses":     persist.Guesses[previous],
+			"game_boards.$.guess_times": persist.GuessTimes[len(persist.GuessTimes)-1],
+		},
+		"$set": set,
+	}
+
+	result, err := s.database.Collection("users").UpdateOne(ctx, filter, update)
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "AddGameBoardGuess"}
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrConflict{Message: "the board changed since it was read", RepoMethod: "AddGameBoardGuess"}
+	}
+	return nil
+}
+
+// AddGameBoardHint saves the last hint of a board. It's only saved if the game isn't over and the
+// board still has the hints before it, otherwise it's reported as ErrConflict.
+func (s *Service) AddGameBoardHint(ctx context.Context, userId string, gameBoard models.GameBoard) error {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	persist := gameBoardModelToPersistedModel(gameBoard)
+	previous := len(persist.Hints) - 1
+	if previous < 0 {
+		return models.ErrRepoFailed{Message: "the board has no hint to add", RepoMethod: "AddGameBoardHint"}
+	}
+
+	board := bson.M{"day": gameBoard.Day, "state": models.GameStateInProgress}
+	matchLength(board, "hints", previous)
+	filter := bson.M{"_id": userOid, "game_boards": bson.M{"$elemMatch": board}}
+	update := bson.M{"$push": bson.M{"game_boards.$.hints": persist.Hints[previous]}}
+
+	result, err := s.database.Collection("users").UpdateOne(ctx, filter, update)
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "AddGameBoardHint"}
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrConflict{Message: "the board changed since it was read", RepoMethod: "AddGameBoardHint"}
+	}
+	return nil
+}
+
+// matchLength adds a filter for an array field with exactly n elements. Unlike $size, it matches a
+// missing field when n is 0, which boards from before the field existed have.
+func matchLength(filter bson.M, field string, n int) {
+	filter[fmt.Sprintf("%s.%d", field, n)] = bson.M{"$exists": false}
+	if n > 0 {
+		filter[fmt.Sprintf("%s.%d", field, n-1)] = bson.M{"$exists": true}
+	}
+}
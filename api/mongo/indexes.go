@@ -3,6 +3,17 @@ package mongo
 import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	userIndexName = "oauth_uuid_unique"
+	// the oauth_uuid index wasn't unique at first, this is its default name
+	legacyUserIndexName = "oauth_uuid_1"
+
+	userIdentitiesIndexName = "identities_unique"
+	// neither was the identities index
+	legacyUserIdentitiesIndexName = "identities_1"
 )
 
 var (
@@ -12,6 +23,59 @@ var (
 	}
 	userIndex = mongo.IndexModel{
 		Keys:    bson.M{"oauth_uuid": 1},
+		Options: options.Index().SetUnique(true).SetName(userIndexName),
+	}
+	// users being merged have no identities while theirs move, so only users with some are indexed
+	userIdentitiesIndex = mongo.IndexModel{
+		Keys: bson.M{"identities": 1},
+		Options: options.Index().
+			SetUnique(true).
+			SetName(userIdentitiesIndexName).
+			SetPartialFilterExpression(bson.M{"identities": bson.M{"$type": "string"}}),
+	}
+	userMergingIdentitiesIndex = mongo.IndexModel{
+		Keys:    bson.M{"merging_identities": 1},
+		Options: nil,
+	}
+	practiceBoardIndex = mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "practice_id", Value: 1}},
+		Options: nil,
+	}
+	absurdleBoardIndex = mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "absurdle_id", Value: 1}},
+		Options: nil,
+	}
+	puzzleIndex = mongo.IndexModel{
+		Keys:    bson.M{"puzzle_id": 1},
+		Options: nil,
+	}
+	puzzleLeaderboardIndex = mongo.IndexModel{
+		Keys:    bson.M{"leaderboard_ids": 1},
+		Options: nil,
+	}
+	accountEmailIndex = mongo.IndexModel{
+		Keys:    bson.M{"email": 1},
+		Options: options.Index().SetUnique(true),
+	}
+	accountUidIndex = mongo.IndexModel{
+		Keys:    bson.M{"uid": 1},
+		Options: options.Index().SetUnique(true),
+	}
+	loginTokenIndex = mongo.IndexModel{
+		Keys:    bson.M{"token_hash": 1},
+		Options: options.Index().SetUnique(true),
+	}
+	accessTokenIndex = mongo.IndexModel{
+		Keys:    bson.M{"token_hash": 1},
+		Options: options.Index().SetUnique(true),
+	}
+	accessTokenUserIndex = mongo.IndexModel{
+		Keys:    bson.M{"user_id": 1},
 		Options: nil,
 	}
+	// expired login tokens are removed by mongo
+	loginTokenExpiryIndex = mongo.IndexModel{
+		Keys:    bson.M{"expires_at": 1},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
 )
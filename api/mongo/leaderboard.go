@@ -17,6 +17,15 @@ type persistLeaderboard struct {
 	Members []primitive.ObjectID `bson:"member_ids"`
 	JoinId  string               `bson:"join_id"`
 	OwnerId primitive.ObjectID   `bson:"owner_id"`
+
+	// leaderboards created before settings existed don't have any
+	Settings *persistedLeaderboardSettings `bson:"settings,omitempty"`
+}
+
+type persistedLeaderboardSettings struct {
+	HintsAllowed    bool `bson:"hints_allowed"`
+	HintPenalty     int  `bson:"hint_penalty"`
+	IncludeImported bool `bson:"include_imported"`
 }
 
 func persistedLeaderboardToModel(lb persistLeaderboard) models.Leaderboard {
@@ -25,12 +34,22 @@ func persistedLeaderboardToModel(lb persistLeaderboard) models.Leaderboard {
 		ids[i] = id.Hex()
 	}
 
+	settings := models.DefaultLeaderboardSettings()
+	if lb.Settings != nil {
+		settings = models.LeaderboardSettings{
+			HintsAllowed:    lb.Settings.HintsAllowed,
+			HintPenalty:     lb.Settings.HintPenalty,
+			IncludeImported: lb.Settings.IncludeImported,
+		}
+	}
+
 	return models.Leaderboard{
 		ID:        lb.JoinId,
 		Name:      lb.Name,
 		MemberIds: ids,
 		StoredId:  lb.Id.Hex(),
 		Owner:     lb.OwnerId.Hex(),
+		Settings:  settings,
 	}
 }
 
@@ -47,6 +66,11 @@ func leaderboardModelToPersisted(lb models.Leaderboard) persistLeaderboard {
 		Members: ids,
 		JoinId:  lb.ID,
 		OwnerId: ownerOid,
+		Settings: &persistedLeaderboardSettings{
+			HintsAllowed:    lb.Settings.HintsAllowed,
+			HintPenalty:     lb.Settings.HintPenalty,
+			IncludeImported: lb.Settings.IncludeImported,
+		},
 	}
 }
 
@@ -133,12 +157,8 @@ func (s *Service) FindLeaderBoardMembers(ctx context.Context, members []string)
 		if err := results.Decode(member); err != nil {
 			return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "FindLeaderBoardMembers"}
 		}
-		model := &models.User{
-			ID:          member.ID.Hex(),
-			DisplayName: member.DisplayName,
-			OauthId:     member.OauthUuid,
-		}
-		foundMembers = append(foundMembers, model)
+		model := persistedUserToModel(*member)
+		foundMembers = append(foundMembers, &model)
 	}
 	if err := results.Err(); err != nil {
 		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "FindLeaderBoardMembers"}
@@ -186,10 +206,14 @@ func (s *Service) FindLeaderboardStatsForMembers(ctx context.Context, members []
 		stats[usr] = make([]models.UserStat, len(member.GameBoards))
 		for i, board := range member.GameBoards {
 			stats[usr][i] = models.UserStat{
-				Day:     board.Day,
-				Guesses: persistedGuessesToModel(board.Guesses),
-				State:   board.State,
-				User:    usr,
+				Day:          board.Day,
+				Guesses:      persistedGuessesToModel(board.Guesses),
+				State:        board.State,
+				User:         usr,
+				FirstGuessAt: board.FirstGuessAt,
+				CompletedAt:  board.CompletedAt,
+				HintsUsed:    len(board.Hints),
+				Imported:     board.Imported,
 			}
 		}
 	}
@@ -241,14 +265,8 @@ func (s *Service) FindGameBoardsForUser(ctx context.Context, userId string) ([]*
 
 	gameBoards := make([]*models.GameBoard, 0)
 	for _, gb := range pu.GameBoards {
-		gameBoards = append(
-			gameBoards,
-			&models.GameBoard{
-				Day:     gb.Day,
-				Guesses: persistedGuessesToModel(gb.Guesses),
-				State:   gb.State,
-			},
-		)
+		board := persistedGameBoardToModel(gb)
+		gameBoards = append(gameBoards, &board)
 	}
 	return gameBoards, nil
 }
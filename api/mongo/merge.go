@@ -0,0 +1,162 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MarkUserMerged leaves a tombstone on a user that's being merged into another one, so a merge that
+// stops part way can be found and finished. Marking a user again keeps the first user they're
+// merged into.
+func (s *Service) MarkUserMerged(ctx context.Context, userId, intoUserId string) error {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	intoOid, _ := primitive.ObjectIDFromHex(intoUserId)
+	collection := s.database.Collection("users")
+
+	filter := bson.M{"_id": userOid, "merged_into": bson.M{"$exists": false}}
+	result, err := collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"merged_into": intoOid}})
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "MarkUserMerged"}
+	}
+	if result.MatchedCount == 0 {
+		count, countErr := collection.CountDocuments(ctx, bson.M{"_id": userOid})
+		if countErr != nil {
+			return models.ErrRepoFailed{Message: countErr.Error(), RepoMethod: "MarkUserMerged"}
+		}
+		if count == 0 {
+			return models.ErrNotFound{Message: fmt.Sprintf("no user with id %s", userId), RepoMethod: "MarkUserMerged"}
+		}
+	}
+	return nil
+}
+
+func (s *Service) FindMergedUsers(ctx context.Context) ([]*models.User, error) {
+	opts := options.Find().SetProjection(bson.M{"game_boards": 0, "multi_boards": 0})
+	results, err := s.database.Collection("users").Find(ctx, bson.M{"merged_into": bson.M{"$exists": true}}, opts)
+	if err != nil {
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "FindMergedUsers"}
+	}
+
+	found := make([]*models.User, 0)
+	for results.Next(ctx) {
+		user := new(persistedUser)
+		if decodeErr := results.Decode(user); decodeErr != nil {
+			return nil, models.ErrRepoFailed{Message: decodeErr.Error(), RepoMethod: "FindMergedUsers"}
+		}
+		model := persistedUserToModel(*user)
+		found = append(found, &model)
+	}
+	if results.Err() != nil {
+		return nil, models.ErrRepoFailed{Message: results.Err().Error(), RepoMethod: "FindMergedUsers"}
+	}
+	return found, nil
+}
+
+// TransferMultiBoards copies one user's multi boards to another. When both played the same day and
+// variant, the board of the user they're copied to is kept.
+func (s *Service) TransferMultiBoards(ctx context.Context, fromUserId, toUserId string) error {
+	fromOid, _ := primitive.ObjectIDFromHex(fromUserId)
+	toOid, _ := primitive.ObjectIDFromHex(toUserId)
+	collection := s.database.Collection("users")
+
+	opt := options.FindOne().SetProjection(bson.M{"multi_boards": 1})
+	from := new(persistedUser)
+	if err := collection.FindOne(ctx, bson.M{"_id": fromOid}, opt).Decode(from); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.ErrNotFound{Message: fmt.Sprintf("no user with id %s", fromUserId), RepoMethod: "TransferMultiBoards"}
+		}
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "TransferMultiBoards"}
+	}
+
+	for _, board := range from.MultiBoards {
+		filter := bson.M{
+			"_id":          toOid,
+			"multi_boards": bson.M{"$not": bson.M{"$elemMatch": bson.M{"day": board.Day, "variant": board.Variant}}},
+		}
+		if _, err := collection.UpdateOne(ctx, filter, bson.M{"$push": bson.M{"multi_boards": board}}); err != nil {
+			return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "TransferMultiBoards"}
+		}
+	}
+	return nil
+}
+
+// TransferAchievements gives one user's achievements to another, unless they already have one of
+// the same kind
+func (s *Service) TransferAchievements(ctx context.Context, fromUserId, toUserId string) error {
+	achievements, err := s.FindAchievements(ctx, fromUserId)
+	if err != nil {
+		return err
+	}
+	for _, achievement := range achievements {
+		if _, unlockErr := s.UnlockAchievement(ctx, toUserId, achievement); unlockErr != nil {
+			return unlockErr
+		}
+	}
+	return nil
+}
+
+// TransferUserGames gives the games a user played outside of their user document to another user:
+// practice games, absurdle games, puzzles they created, and their boards on other puzzles. When
+// both played the same game, the board of the user they're given to is kept.
+func (s *Service) TransferUserGames(ctx context.Context, fromUserId, toUserId string) error {
+	fromOid, _ := primitive.ObjectIDFromHex(fromUserId)
+	toOid, _ := primitive.ObjectIDFromHex(toUserId)
+	if err := s.transferBoards(ctx, "practice_boards", "practice_id", fromOid, toOid); err != nil {
+		return err
+	}
+	if err := s.transferBoards(ctx, "absurdle_boards", "absurdle_id", fromOid, toOid); err != nil {
+		return err
+	}
+
+	puzzles := s.database.Collection("puzzles")
+	_, err := puzzles.UpdateMany(ctx, bson.M{"creator_id": fromOid}, bson.M{"$set": bson.M{"creator_id": toOid}})
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "TransferUserGames"}
+	}
+	// boards move on puzzles only the old user played, and are dropped where both played
+	_, err = puzzles.UpdateMany(
+		ctx,
+		bson.M{"$and": bson.A{
+			bson.M{"boards.user_id": fromOid},
+			bson.M{"boards.user_id": bson.M{"$ne": toOid}},
+		}},
+		bson.M{"$set": bson.M{"boards.$[board].user_id": toOid}},
+		options.Update().SetArrayFilters(options.ArrayFilters{Filters: bson.A{bson.M{"board.user_id": fromOid}}}),
+	)
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "TransferUserGames"}
+	}
+	_, err = puzzles.UpdateMany(
+		ctx,
+		bson.M{"boards.user_id": fromOid},
+		bson.M{"$pull": bson.M{"boards": bson.M{"user_id": fromOid}}},
+	)
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "TransferUserGames"}
+	}
+	return nil
+}
+
+func (s *Service) transferBoards(ctx context.Context, name, idField string, fromOid, toOid primitive.ObjectID) error {
+	collection := s.database.Collection(name)
+	played, err := collection.Distinct(ctx, idField, bson.M{"user_id": toOid})
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "TransferUserGames"}
+	}
+	if len(played) > 0 {
+		_, err = collection.DeleteMany(ctx, bson.M{"user_id": fromOid, idField: bson.M{"$in": played}})
+		if err != nil {
+			return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "TransferUserGames"}
+		}
+	}
+	if _, err = collection.UpdateMany(ctx, bson.M{"user_id": fromOid}, bson.M{"$set": bson.M{"user_id": toOid}}); err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "TransferUserGames"}
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package mongo
 
 import (
 	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"time"
@@ -31,7 +33,33 @@ func NewMongoService(connection string) (*Service, error) {
 	if err != nil {
 		return nil, err
 	}
-	_, err = db.Collection("users").Indexes().CreateOne(ctx, userIndex)
+	// the unique oauth_uuid and identities indexes are created by CreateUniqueUserIndexes, once
+	// duplicate users are merged
+	_, err = db.Collection("users").Indexes().CreateOne(ctx, userMergingIdentitiesIndex)
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Collection("practice_boards").Indexes().CreateOne(ctx, practiceBoardIndex)
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Collection("absurdle_boards").Indexes().CreateOne(ctx, absurdleBoardIndex)
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Collection("puzzles").Indexes().CreateMany(ctx, []mongo.IndexModel{puzzleIndex, puzzleLeaderboardIndex})
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Collection("accounts").Indexes().CreateMany(ctx, []mongo.IndexModel{accountEmailIndex, accountUidIndex})
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Collection("login_tokens").Indexes().CreateMany(ctx, []mongo.IndexModel{loginTokenIndex, loginTokenExpiryIndex})
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Collection("access_tokens").Indexes().CreateMany(ctx, []mongo.IndexModel{accessTokenIndex, accessTokenUserIndex})
 	if err != nil {
 		return nil, err
 	}
@@ -43,6 +71,49 @@ func NewMongoService(connection string) (*Service, error) {
 		nil
 }
 
+// CreateUniqueUserIndexes replaces the old oauth_uuid and identities indexes with unique ones.
+// Users from before every uid was kept in identities get their oauth uid added to them first, so
+// the identities index alone keeps a uid from signing in to two users. It fails if there are still
+// users with the same uid.
+func (s *Service) CreateUniqueUserIndexes(ctx context.Context) error {
+	collection := s.database.Collection("users")
+	if err := dropLegacyIndex(ctx, collection, legacyUserIndexName); err != nil {
+		return err
+	}
+	if _, err := collection.Indexes().CreateOne(ctx, userIndex); err != nil {
+		return err
+	}
+
+	// users being merged have moved their uids to merging_identities
+	filter := bson.M{
+		"merged_into": bson.M{"$exists": false},
+		"$expr":       bson.M{"$not": bson.M{"$in": bson.A{"$oauth_uuid", bson.M{"$ifNull": bson.A{"$identities", bson.A{}}}}}},
+	}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"identities": bson.M{"$setUnion": bson.A{bson.M{"$ifNull": bson.A{"$identities", bson.A{}}}, bson.A{"$oauth_uuid"}}},
+	}}}}
+	if _, err := collection.UpdateMany(ctx, filter, update); err != nil {
+		return err
+	}
+
+	if err := dropLegacyIndex(ctx, collection, legacyUserIdentitiesIndexName); err != nil {
+		return err
+	}
+	_, err := collection.Indexes().CreateOne(ctx, userIdentitiesIndex)
+	return err
+}
+
+func dropLegacyIndex(ctx context.Context, collection *mongo.Collection, name string) error {
+	if _, err := collection.Indexes().DropOne(ctx, name); err != nil {
+		// NamespaceNotFound or IndexNotFound, the old index is already gone
+		var commandErr mongo.CommandError
+		if !errors.As(err, &commandErr) || (commandErr.Code != 26 && commandErr.Code != 27) {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *Service) Disconnect(ctx context.Context) error {
 	return s.client.Disconnect(ctx)
 }
@@ -0,0 +1,163 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type persistedMultiBoard struct {
+	Day     int                      `bson:"day"`
+	Variant models.MultiBoardVariant `bson:"variant"`
+	Boards  []persistedSubBoard      `bson:"boards"`
+	State   models.GameState         `bson:"state"`
+}
+
+type persistedSubBoard struct {
+	Guesses [][]guess        `bson:"guesses"`
+	State   models.GameState `bson:"state"`
+}
+
+func multiBoardModelToPersisted(board models.MultiBoard) persistedMultiBoard {
+	subBoards := make([]persistedSubBoard, len(board.Boards))
+	for i, subBoard := range board.Boards {
+		subBoards[i] = persistedSubBoard{
+			Guesses: guessesModelToPersisted(subBoard.Guesses),
+			State:   subBoard.State,
+		}
+	}
+	return persistedMultiBoard{
+		Day:     board.Day,
+		Variant: board.Variant,
+		Boards:  subBoards,
+		State:   board.State,
+	}
+}
+
+func persistedMultiBoardToModel(board persistedMultiBoard) models.MultiBoard {
+	subBoards := make([]models.SubBoard, len(board.Boards))
+	for i, subBoard := range board.Boards {
+		subBoards[i] = models.SubBoard{
+			Guesses: persistedGuessesToModel(subBoard.Guesses),
+			State:   subBoard.State,
+		}
+	}
+	return models.MultiBoard{
+		Day:     board.Day,
+		Variant: board.Variant,
+		Boards:  subBoards,
+		State:   board.State,
+	}
+}
+
+func (s *Service) FindMultiBoardByUserAndDay(ctx context.Context, userId string, day int, variant models.MultiBoardVariant) (*models.MultiBoard, error) {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	projection := bson.M{"multi_boards": bson.M{"$elemMatch": bson.M{"day": day, "variant": variant}}}
+	opt := options.FindOne().SetProjection(projection)
+
+	collection := s.database.Collection("users")
+	doc := collection.FindOne(ctx, bson.M{"_id": userOid}, opt)
+	if documentErr := doc.Err(); documentErr != nil {
+		if errors.Is(documentErr, mongo.ErrNoDocuments) {
+			return nil, models.ErrRepoFailed{Message: "invalid state, no user", RepoMethod: "FindMultiBoardByUserAndDay"}
+		}
+		return nil, models.ErrRepoFailed{Message: documentErr.Error(), RepoMethod: "FindMultiBoardByUserAndDay"}
+	}
+
+	user := new(persistedUser)
+	err := doc.Decode(user)
+	if err != nil {
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "FindMultiBoardByUserAndDay"}
+	}
+
+	if len(user.MultiBoards) == 0 {
+		return nil, models.ErrNotFound{RepoMethod: "FindMultiBoardByUserAndDay", Message: "no multi boards found for user"}
+	}
+	model := persistedMultiBoardToModel(user.MultiBoards[0])
+	return &model, nil
+}
+
+func (s *Service) InsertMultiBoard(ctx context.Context, userId string, board models.MultiBoard) error {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	persist := multiBoardModelToPersisted(board)
+
+	collection := s.database.Collection("users")
+	update := bson.M{"$push": bson.M{"multi_boards": persist}}
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": userOid}, update)
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "InsertMultiBoard"}
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrRepoFailed{Message: fmt.Sprintf("no user with id %s found", userId), RepoMethod: "InsertMultiBoard"}
+	}
+	return nil
+}
+
+func (s *Service) UpdateMultiBoard(ctx context.Context, userId string, board models.MultiBoard) error {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	persist := multiBoardModelToPersisted(board)
+
+	collection := s.database.Collection("users")
+	filter := bson.M{
+		"_id":          userOid,
+		"multi_boards": bson.M{"$elemMatch": bson.M{"day": board.Day, "variant": board.Variant}},
+	}
+	update := bson.M{"$set": bson.M{"multi_boards.$": persist}}
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "UpdateMultiBoard"}
+	} else if result.MatchedCount == 0 {
+		return models.ErrNotFound{RepoMethod: "UpdateMultiBoard", Message: "did not update any documents"}
+	}
+	return nil
+}
+
+func (s *Service) FindMultiBoardStatsForMembers(ctx context.Context, members []string, variant models.MultiBoardVariant) (map[models.User][]models.MultiBoardUserStat, error) {
+	collection := s.database.Collection("users")
+	oids := bson.A{}
+	for _, id := range members {
+		oid, _ := primitive.ObjectIDFromHex(id)
+		oids = append(oids, oid)
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": oids}}
+	opts := options.Find().SetProjection(bson.M{"game_boards": 0})
+
+	results, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "FindMultiBoardStatsForMembers"}
+	}
+
+	stats := make(map[models.User][]models.MultiBoardUserStat)
+	for results.Next(ctx) {
+		member := new(persistedUser)
+		if err := results.Decode(member); err != nil {
+			return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "FindMultiBoardStatsForMembers"}
+		}
+
+		usr := persistedUserToModel(*member)
+		stats[usr] = make([]models.MultiBoardUserStat, 0)
+		for _, persisted := range member.MultiBoards {
+			if persisted.Variant != variant {
+				continue
+			}
+			board := persistedMultiBoardToModel(persisted)
+			stats[usr] = append(stats[usr], models.MultiBoardUserStat{
+				Day:        board.Day,
+				Boards:     board.Boards,
+				State:      board.State,
+				GuessCount: board.GuessCount(),
+				User:       usr,
+			})
+		}
+	}
+	if err := results.Err(); err != nil {
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "FindMultiBoardStatsForMembers"}
+	}
+	return stats, nil
+}
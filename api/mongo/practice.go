@@ -0,0 +1,89 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type persistedPracticeBoard struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty"`
+	PracticeId string             `bson:"practice_id"`
+	UserId     primitive.ObjectID `bson:"user_id"`
+	Solution   string             `bson:"solution"`
+	Guesses    [][]guess          `bson:"guesses"`
+	State      models.GameState   `bson:"state"`
+}
+
+func practiceBoardModelToPersisted(board models.PracticeBoard) persistedPracticeBoard {
+	userOid, _ := primitive.ObjectIDFromHex(board.UserId)
+	return persistedPracticeBoard{
+		PracticeId: board.ID,
+		UserId:     userOid,
+		Solution:   board.Word,
+		Guesses:    guessesModelToPersisted(board.Guesses),
+		State:      board.State,
+	}
+}
+
+func persistedPracticeBoardToModel(board persistedPracticeBoard) models.PracticeBoard {
+	return models.PracticeBoard{
+		ID:      board.PracticeId,
+		UserId:  board.UserId.Hex(),
+		Word:    board.Solution,
+		Guesses: persistedGuessesToModel(board.Guesses),
+		State:   board.State,
+	}
+}
+
+func (s *Service) InsertPracticeBoard(ctx context.Context, board models.PracticeBoard) error {
+	collection := s.database.Collection("practice_boards")
+	_, insertErr := collection.InsertOne(ctx, practiceBoardModelToPersisted(board))
+	if insertErr != nil {
+		return models.ErrRepoFailed{Message: insertErr.Error(), RepoMethod: "InsertPracticeBoard"}
+	}
+	return nil
+}
+
+func (s *Service) FindPracticeBoard(ctx context.Context, userId, id string) (*models.PracticeBoard, error) {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	collection := s.database.Collection("practice_boards")
+	result := collection.FindOne(ctx, bson.M{"practice_id": id, "user_id": userOid})
+	if result.Err() != nil {
+		if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+			return nil, models.ErrNotFound{Message: fmt.Sprintf("no practice board with id %s", id), RepoMethod: "FindPracticeBoard"}
+		}
+		return nil, models.ErrRepoFailed{Message: result.Err().Error(), RepoMethod: "FindPracticeBoard"}
+	}
+
+	board := new(persistedPracticeBoard)
+	decodeErr := result.Decode(board)
+	if decodeErr != nil {
+		return nil, models.ErrRepoFailed{Message: decodeErr.Error(), RepoMethod: "FindPracticeBoard"}
+	}
+
+	model := persistedPracticeBoardToModel(*board)
+	return &model, nil
+}
+
+func (s *Service) UpdatePracticeBoard(ctx context.Context, board models.PracticeBoard) error {
+	userOid, _ := primitive.ObjectIDFromHex(board.UserId)
+	collection := s.database.Collection("practice_boards")
+	filter := bson.M{"practice_id": board.ID, "user_id": userOid}
+	update := bson.M{"$set": bson.M{
+		"guesses": guessesModelToPersisted(board.Guesses),
+		"state":   board.State,
+	}}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "UpdatePracticeBoard"}
+	} else if result.MatchedCount == 0 {
+		return models.ErrNotFound{Message: fmt.Sprintf("no practice board with id %s", board.ID), RepoMethod: "UpdatePracticeBoard"}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+type persistedPuzzle struct {
+	Id             primitive.ObjectID     `bson:"_id,omitempty"`
+	PuzzleId       string                 `bson:"puzzle_id"`
+	CreatorId      primitive.ObjectID     `bson:"creator_id"`
+	Solution       string                 `bson:"solution"`
+	LeaderboardIds []string               `bson:"leaderboard_ids"`
+	CreatedAt      time.Time              `bson:"created_at"`
+	Boards         []persistedPuzzleBoard `bson:"boards"`
+}
+
+type persistedPuzzleBoard struct {
+	UserId  primitive.ObjectID `bson:"user_id"`
+	Guesses [][]guess          `bson:"guesses"`
+	State   models.GameState   `bson:"state"`
+}
+
+func puzzleBoardModelToPersisted(board models.PuzzleBoard) persistedPuzzleBoard {
+	userOid, _ := primitive.ObjectIDFromHex(board.UserId)
+	return persistedPuzzleBoard{
+		UserId:  userOid,
+		Guesses: guessesModelToPersisted(board.Guesses),
+		State:   board.State,
+	}
+}
+
+func persistedPuzzleToModel(puzzle persistedPuzzle) models.Puzzle {
+	boards := make([]models.PuzzleBoard, len(puzzle.Boards))
+	for i, board := range puzzle.Boards {
+		boards[i] = models.PuzzleBoard{
+			PuzzleId: puzzle.PuzzleId,
+			UserId:   board.UserId.Hex(),
+			Guesses:  persistedGuessesToModel(board.Guesses),
+			State:    board.State,
+		}
+	}
+	leaderboardIds := puzzle.LeaderboardIds
+	if leaderboardIds == nil {
+		leaderboardIds = make([]string, 0)
+	}
+	return models.Puzzle{
+		ID:           puzzle.PuzzleId,
+		Creator:      puzzle.CreatorId.Hex(),
+		Word:         puzzle.Solution,
+		Leaderboards: leaderboardIds,
+		CreatedAt:    puzzle.CreatedAt,
+		Boards:       boards,
+	}
+}
+
+func (s *Service) InsertPuzzle(ctx context.Context, puzzle models.Puzzle) error {
+	creatorOid, _ := primitive.ObjectIDFromHex(puzzle.Creator)
+	boards := make([]persistedPuzzleBoard, len(puzzle.Boards))
+	for i, board := range puzzle.Boards {
+		boards[i] = puzzleBoardModelToPersisted(board)
+	}
+	persist := persistedPuzzle{
+		PuzzleId:       puzzle.ID,
+		CreatorId:      creatorOid,
+		Solution:       puzzle.Word,
+		LeaderboardIds: puzzle.Leaderboards,
+		CreatedAt:      puzzle.CreatedAt,
+		Boards:         boards,
+	}
+
+	collection := s.database.Collection("puzzles")
+	_, insertErr := collection.InsertOne(ctx, persist)
+	if insertErr != nil {
+		return models.ErrRepoFailed{Message: insertErr.Error(), RepoMethod: "InsertPuzzle"}
+	}
+	return nil
+}
+
+func (s *Service) FindPuzzle(ctx context.Context, id string) (*models.Puzzle, error) {
+	collection := s.database.Collection("puzzles")
+	result := collection.FindOne(ctx, bson.M{"puzzle_id": id})
+	if result.Err() != nil {
+		if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+			return nil, models.ErrNotFound{Message: fmt.Sprintf("no puzzle with id %s", id), RepoMethod: "FindPuzzle"}
+		}
+		return nil, models.ErrRepoFailed{Message: result.Err().Error(), RepoMethod: "FindPuzzle"}
+	}
+
+	puzzle := new(persistedPuzzle)
+	decodeErr := result.Decode(puzzle)
+	if decodeErr != nil {
+		return nil, models.ErrRepoFailed{Message: decodeErr.Error(), RepoMethod: "FindPuzzle"}
+	}
+
+	model := persistedPuzzleToModel(*puzzle)
+	return &model, nil
+}
+
+func (s *Service) FindPuzzlesForLeaderboard(ctx context.Context, joinId string) ([]*models.Puzzle, error) {
+	collection := s.database.Collection("puzzles")
+	results, err := collection.Find(ctx, bson.M{"leaderboard_ids": joinId})
+	if err != nil {
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "FindPuzzlesForLeaderboard"}
+	}
+
+	puzzles := make([]*models.Puzzle, 0)
+	for results.Next(ctx) {
+		puzzle := new(persistedPuzzle)
+		if decodeErr := results.Decode(puzzle); decodeErr != nil {
+			return nil, models.ErrRepoFailed{Message: decodeErr.Error(), RepoMethod: "FindPuzzlesForLeaderboard"}
+		}
+		model := persistedPuzzleToModel(*puzzle)
+		puzzles = append(puzzles, &model)
+	}
+	if results.Err() != nil {
+		return nil, models.ErrRepoFailed{Message: results.Err().Error(), RepoMethod: "FindPuzzlesForLeaderboard"}
+	}
+	return puzzles, nil
+}
+
+// UpsertPuzzleBoard replaces the user's board on a puzzle, adding it if this is their first guess
+func (s *Service) UpsertPuzzleBoard(ctx context.Context, board models.PuzzleBoard) error {
+	persist := puzzleBoardModelToPersisted(board)
+	collection := s.database.Collection("puzzles")
+
+	filter := bson.M{"puzzle_id": board.PuzzleId, "boards.user_id": persist.UserId}
+	result, err := collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"boards.$": persist}})
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "UpsertPuzzleBoard"}
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	filter = bson.M{"puzzle_id": board.PuzzleId, "boards.user_id": bson.M{"$ne": persist.UserId}}
+	result, err = collection.UpdateOne(ctx, filter, bson.M{"$push": bson.M{"boards": persist}})
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "UpsertPuzzleBoard"}
+	} else if result.MatchedCount == 0 {
+		return models.ErrNotFound{Message: fmt.Sprintf("no puzzle with id %s", board.PuzzleId), RepoMethod: "UpsertPuzzleBoard"}
+	}
+	return nil
+}
+
+func (s *Service) AttachPuzzleToLeaderboard(ctx context.Context, puzzleId, joinId string) error {
+	collection := s.database.Collection("puzzles")
+	update := bson.M{"$addToSet": bson.M{"leaderboard_ids": joinId}}
+	result, err := collection.UpdateOne(ctx, bson.M{"puzzle_id": puzzleId}, update)
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "AttachPuzzleToLeaderboard"}
+	} else if result.MatchedCount == 0 {
+		return models.ErrNotFound{Message: fmt.Sprintf("no puzzle with id %s", puzzleId), RepoMethod: "AttachPuzzleToLeaderboard"}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"github.com/amanzanero/wordleboard/api/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type persistedStatistics struct {
+	GamesPlayed     int   `bson:"games_played"`
+	GamesWon        int   `bson:"games_won"`
+	Streak          int   `bson:"streak"`
+	MaxStreak       int   `bson:"max_streak"`
+	LastWinDay      int   `bson:"last_win_day"`
+	LastPlayedDay   int   `bson:"last_played_day"`
+	Distribution    []int `bson:"distribution"`
+	TotalWinGuesses int   `bson:"total_win_guesses"`
+}
+
+func (s *Service) FindUserStatistics(ctx context.Context, userId string) (*models.UserStatistics, error) {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	opt := options.FindOne().SetProjection(bson.M{"statistics": 1})
+
+	collection := s.database.Collection("users")
+	doc := collection.FindOne(ctx, bson.M{"_id": userOid}, opt)
+	if doc.Err() != nil {
+		if errors.Is(doc.Err(), mongo.ErrNoDocuments) {
+			return nil, models.ErrRepoFailed{Message: "invalid state, no user", RepoMethod: "FindUserStatistics"}
+		}
+		return nil, models.ErrRepoFailed{Message: doc.Err().Error(), RepoMethod: "FindUserStatistics"}
+	}
+
+	user := new(struct {
+		Statistics *persistedStatistics `bson:"statistics"`
+	})
+	if decodeErr := doc.Decode(user); decodeErr != nil {
+		return nil, models.ErrRepoFailed{Message: decodeErr.Error(), RepoMethod: "FindUserStatistics"}
+	}
+	if user.Statistics == nil {
+		return nil, models.ErrNotFound{Message: "statistics have not been computed", RepoMethod: "FindUserStatistics"}
+	}
+
+	stats := user.Statistics
+	return &models.UserStatistics{
+		GamesPlayed:     stats.GamesPlayed,
+		GamesWon:        stats.GamesWon,
+		Streak:          stats.Streak,
+		MaxStreak:       stats.MaxStreak,
+		LastWinDay:      stats.LastWinDay,
+		LastPlayedDay:   stats.LastPlayedDay,
+		Distribution:    stats.Distribution,
+		TotalWinGuesses: stats.TotalWinGuesses,
+	}, nil
+}
+
+// SaveUserStatistics writes statistics only if they haven't changed since previous was read, so two
+// games finishing at once can't overwrite each other. previous is nil if there were none.
+func (s *Service) SaveUserStatistics(ctx context.Context, userId string, stats models.UserStatistics, previous *models.UserStatistics) error {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	filter := bson.M{"_id": userOid}
+	if previous == nil {
+		filter["statistics"] = bson.M{"$exists": false}
+	} else {
+		filter["statistics.games_played"] = previous.GamesPlayed
+		filter["statistics.last_played_day"] = previous.LastPlayedDay
+	}
+
+	persist := persistedStatistics{
+		GamesPlayed:     stats.GamesPlayed,
+		GamesWon:        stats.GamesWon,
+		Streak:          stats.Streak,
+		MaxStreak:       stats.MaxStreak,
+		LastWinDay:      stats.LastWinDay,
+		LastPlayedDay:   stats.LastPlayedDay,
+		Distribution:    stats.Distribution,
+		TotalWinGuesses: stats.TotalWinGuesses,
+	}
+	collection := s.database.Collection("users")
+	result, err := collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"statistics": persist}})
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "SaveUserStatistics"}
+	} else if result.MatchedCount == 0 {
+		return models.ErrConflict{Message: "statistics changed since they were read", RepoMethod: "SaveUserStatistics"}
+	}
+	return nil
+}
+
+// ClearUserStatistics removes a user's statistics, so they're computed from their boards the next
+// time they're read
+func (s *Service) ClearUserStatistics(ctx context.Context, userId string) error {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	_, err := s.database.Collection("users").UpdateOne(ctx, bson.M{"_id": userOid}, bson.M{"$unset": bson.M{"statistics": ""}})
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "ClearUserStatistics"}
+	}
+	return nil
+}
@@ -3,85 +3,381 @@ package mongo
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/amanzanero/wordleboard/api/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
 )
 
 type persistedUser struct {
-	ID          primitive.ObjectID   `bson:"_id,omitempty"`
-	DisplayName string               `bson:"display_name"`
-	OauthUuid   string               `bson:"oauth_uuid"`
-	GameBoards  []persistedGameBoard `bson:"game_boards"`
+	ID          primitive.ObjectID    `bson:"_id,omitempty"`
+	DisplayName string                `bson:"display_name"`
+	OauthUuid   string                `bson:"oauth_uuid"`
+	Identities  []string              `bson:"identities,omitempty"`
+	GameBoards  []persistedGameBoard  `bson:"game_boards"`
+	MultiBoards []persistedMultiBoard `bson:"multi_boards"`
+	AvatarUrl   *string               `bson:"avatar_url,omitempty"`
+	Preferences *persistedPreferences `bson:"preferences,omitempty"`
+	DeletedAt   *time.Time            `bson:"deleted_at,omitempty"`
+	Admin       bool                  `bson:"admin,omitempty"`
+	BannedAt    *time.Time            `bson:"banned_at,omitempty"`
+	BanReason   *string               `bson:"ban_reason,omitempty"`
+	MergedInto  *primitive.ObjectID   `bson:"merged_into,omitempty"`
+
+	// a user being merged keeps their uids here while they move to the user they're merged into
+	MergingIdentities []string `bson:"merging_identities,omitempty"`
+}
+
+type persistedPreferences struct {
+	TimeZone        string              `bson:"time_zone"`
+	HardModeDefault bool                `bson:"hard_mode_default"`
+	Palette         models.SharePalette `bson:"palette"`
 }
 
 func persistedUserToModel(pu persistedUser) models.User {
+	preferences := models.DefaultUserPreferences()
+	if pu.Preferences != nil {
+		preferences = models.UserPreferences{
+			TimeZone:        pu.Preferences.TimeZone,
+			HardModeDefault: pu.Preferences.HardModeDefault,
+			Palette:         pu.Preferences.Palette,
+		}
+	}
+	var mergedInto *string
+	if pu.MergedInto != nil {
+		hex := pu.MergedInto.Hex()
+		mergedInto = &hex
+	}
 	return models.User{
 		ID:          pu.ID.Hex(),
 		DisplayName: pu.DisplayName,
 		OauthId:     pu.OauthUuid,
+		AvatarUrl:   pu.AvatarUrl,
+		Preferences: preferences,
+		DeletedAt:   pu.DeletedAt,
+		IsAdmin:     pu.Admin,
+		BannedAt:    pu.BannedAt,
+		BanReason:   pu.BanReason,
+		MergedInto:  mergedInto,
 	}
 }
 
 func (s *Service) FindUserById(ctx context.Context, userId string) (*models.User, error) {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	return s.findUserByOid(ctx, userOid, "FindUserById")
+}
+
+func (s *Service) FindUserByUuid(ctx context.Context, oauthUuid string) (*models.User, error) {
 	col := s.database.Collection("users")
-	doc := col.FindOne(ctx, bson.M{"_id": userId})
+	// a user being merged is only found while their uids haven't moved to the user they're merged into
+	opt := options.FindOne().SetProjection(bson.M{"game_boards": 0}).SetSort(bson.M{"merged_into": 1})
+	doc := col.FindOne(ctx, userUidFilter(oauthUuid), opt)
 	if doc.Err() != nil {
 		if errors.Is(doc.Err(), mongo.ErrNoDocuments) {
-			return nil, models.ErrNotFound{Message: doc.Err().Error(), RepoMethod: "FindUserById"}
+			return nil, models.ErrNotFound{Message: doc.Err().Error(), RepoMethod: "FindUserByUuid"}
 		}
-		return nil, models.ErrRepoFailed{Message: doc.Err().Error(), RepoMethod: "FindUserById"}
+		return nil, models.ErrRepoFailed{Message: doc.Err().Error(), RepoMethod: "FindUserByUuid"}
 	}
 
 	pUser := new(persistedUser)
 	err := doc.Decode(pUser)
 	if err != nil {
-		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "FindUserById"}
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "FindUserByUuid"}
 	}
-
 	model := persistedUserToModel(*pUser)
 	return &model, nil
 }
 
-func (s *Service) FindUserByUuid(ctx context.Context, oauthUuid string) (*models.User, error) {
+// userUidFilter matches the user for a uid, whether it's the one they signed up with, one linked to
+// them since, or one that's moving to them from a user being merged
+func userUidFilter(uid string) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"oauth_uuid": uid},
+		bson.M{"identities": uid},
+		bson.M{"merging_identities": uid},
+	}}
+}
+
+// UpsertUser creates a user for an oauth uid unless one already has it, and reports whether it was
+// created. The uid is kept in identities too, so the unique identities index makes it safe to call
+// concurrently for the same uid, and while the uid is being linked to another user.
+func (s *Service) UpsertUser(ctx context.Context, user models.NewUser) (*models.User, bool, error) {
 	col := s.database.Collection("users")
-	opt := options.FindOne().SetProjection(bson.M{"game_boards": 0})
-	doc := col.FindOne(ctx, bson.M{"oauth_uuid": oauthUuid}, opt)
-	if doc.Err() != nil {
-		if errors.Is(doc.Err(), mongo.ErrNoDocuments) {
-			return nil, models.ErrNotFound{Message: doc.Err().Error(), RepoMethod: "FindUserByUuid"}
+	filter := userUidFilter(user.ID)
+	update := bson.M{"$setOnInsert": bson.M{
+		"oauth_uuid":   user.ID,
+		"identities":   bson.A{user.ID},
+		"display_name": user.DisplayName,
+		"game_boards":  make([]persistedGameBoard, 0),
+	}}
+	result, err := upsertOnce(ctx, col, filter, update)
+	if err != nil {
+		return nil, false, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "UpsertUser"}
+	}
+
+	opts := options.FindOne().
+		SetProjection(bson.M{"game_boards": 0, "multi_boards": 0}).
+		SetSort(bson.M{"merged_into": 1})
+	found, findErr := decodeUserResult(col.FindOne(ctx, filter, opts), "UpsertUser")
+	if findErr != nil {
+		return nil, false, findErr
+	}
+	return found, result.UpsertedCount > 0, nil
+}
+
+// updater is the part of a collection upsertOnce writes with
+type updater interface {
+	UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
+}
+
+// upsertOnce runs an upsert that inserts a document when none matches the filter. Concurrent
+// upserts can all miss and try to insert, a unique index then fails all but one of them, and they're
+// run again so they match the document that was inserted.
+func upsertOnce(ctx context.Context, collection updater, filter, update interface{}) (*mongo.UpdateResult, error) {
+	result, err := collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		result, err = collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	}
+	return result, err
+}
+
+// FindDuplicateUsers finds users that share a uid, either one they signed up with or one linked to
+// them, which could be created before the oauth_uuid and identities indexes were unique. Each group
+// is ordered oldest first, and a user can be in more than one group. Users being merged are left
+// out, their merges are finished separately.
+func (s *Service) FindDuplicateUsers(ctx context.Context) ([][]*models.User, error) {
+	uids := bson.M{"$setUnion": bson.A{bson.A{"$oauth_uuid"}, bson.M{"$ifNull": bson.A{"$identities", bson.A{}}}}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"merged_into": bson.M{"$exists": false}}}},
+		{{Key: "$project", Value: bson.M{"game_boards": 0, "multi_boards": 0}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+		{{Key: "$addFields", Value: bson.M{"uids": uids}}},
+		{{Key: "$unwind", Value: "$uids"}},
+		{{Key: "$group", Value: bson.M{"_id": "$uids", "users": bson.M{"$push": "$$ROOT"}}}},
+		{{Key: "$match", Value: bson.M{"users.1": bson.M{"$exists": true}}}},
+	}
+	results, err := s.database.Collection("users").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "FindDuplicateUsers"}
+	}
+
+	duplicates := make([][]*models.User, 0)
+	for results.Next(ctx) {
+		group := new(struct {
+			Users []persistedUser `bson:"users"`
+		})
+		if decodeErr := results.Decode(group); decodeErr != nil {
+			return nil, models.ErrRepoFailed{Message: decodeErr.Error(), RepoMethod: "FindDuplicateUsers"}
 		}
-		return nil, models.ErrRepoFailed{Message: doc.Err().Error(), RepoMethod: "FindUserByUuid"}
+		users := make([]*models.User, len(group.Users))
+		for i, user := range group.Users {
+			model := persistedUserToModel(user)
+			users[i] = &model
+		}
+		duplicates = append(duplicates, users)
+	}
+	if results.Err() != nil {
+		return nil, models.ErrRepoFailed{Message: results.Err().Error(), RepoMethod: "FindDuplicateUsers"}
 	}
+	return duplicates, nil
+}
 
-	pUser := new(persistedUser)
-	err := doc.Decode(pUser)
+// ReplaceGameBoards swaps out all of a user's daily boards. Their statistics are cleared, so
+// they're computed from the new boards the next time they're read.
+func (s *Service) ReplaceGameBoards(ctx context.Context, userId string, boards []models.GameBoard) error {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	persist := make([]persistedGameBoard, len(boards))
+	for i, board := range boards {
+		persist[i] = gameBoardModelToPersistedModel(board)
+	}
+
+	collection := s.database.Collection("users")
+	update := bson.M{
+		"$set":   bson.M{"game_boards": persist},
+		"$unset": bson.M{"statistics": ""},
+	}
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": userOid}, update)
 	if err != nil {
-		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "FindUserByUuid"}
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "ReplaceGameBoards"}
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrNotFound{Message: fmt.Sprintf("no user with id %s", userId), RepoMethod: "ReplaceGameBoards"}
+	}
+	return nil
+}
+
+func (s *Service) DeleteUser(ctx context.Context, userId string) error {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	_, err := s.database.Collection("users").DeleteOne(ctx, bson.M{"_id": userOid})
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "DeleteUser"}
+	}
+	return nil
+}
+
+func (s *Service) AddIdentities(ctx context.Context, userId string, uids []string) error {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	update := bson.M{"$addToSet": bson.M{"identities": bson.M{"$each": uids}}}
+	result, err := s.database.Collection("users").UpdateOne(ctx, bson.M{"_id": userOid}, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return models.ErrAlreadyExists{Message: "a uid already belongs to another user", RepoMethod: "AddIdentities"}
+	} else if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "AddIdentities"}
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrNotFound{Message: fmt.Sprintf("no user with id %s", userId), RepoMethod: "AddIdentities"}
+	}
+	return nil
+}
+
+// TransferIdentities links every uid of one user to another, including the one they signed up with.
+// The uids move to the user's merging_identities first, since a uid can only be in the identities
+// of one user, and they stay there so the transfer can be repeated if it stops part way.
+func (s *Service) TransferIdentities(ctx context.Context, fromUserId, toUserId string) error {
+	uids, err := s.FindIdentities(ctx, fromUserId)
+	if err != nil {
+		return err
+	}
+	fromOid, _ := primitive.ObjectIDFromHex(fromUserId)
+	update := bson.M{
+		"$addToSet": bson.M{"merging_identities": bson.M{"$each": uids}},
+		"$pull":     bson.M{"identities": bson.M{"$in": uids}},
+	}
+	if _, err = s.database.Collection("users").UpdateOne(ctx, bson.M{"_id": fromOid}, update); err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "TransferIdentities"}
+	}
+	return s.AddIdentities(ctx, toUserId, uids)
+}
+
+// FindIdentities finds every uid a user can sign in with, starting with the one they signed up with
+func (s *Service) FindIdentities(ctx context.Context, userId string) ([]string, error) {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	opt := options.FindOne().SetProjection(bson.M{"oauth_uuid": 1, "identities": 1, "merging_identities": 1})
+	result := s.database.Collection("users").FindOne(ctx, bson.M{"_id": userOid}, opt)
+	if result.Err() != nil {
+		if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+			return nil, models.ErrNotFound{Message: fmt.Sprintf("no user with id %s", userId), RepoMethod: "FindIdentities"}
+		}
+		return nil, models.ErrRepoFailed{Message: result.Err().Error(), RepoMethod: "FindIdentities"}
+	}
+
+	user := new(persistedUser)
+	if err := result.Decode(user); err != nil {
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: "FindIdentities"}
+	}
+	uids := []string{user.OauthUuid}
+	seen := map[string]bool{user.OauthUuid: true}
+	for _, uid := range append(user.Identities, user.MergingIdentities...) {
+		if !seen[uid] {
+			seen[uid] = true
+			uids = append(uids, uid)
+		}
+	}
+	return uids, nil
+}
+
+// TransferLeaderboards gives every leaderboard membership and ownership of one user to another.
+// Leaderboards they were both in end up with a single membership.
+func (s *Service) TransferLeaderboards(ctx context.Context, fromUserId, toUserId string) error {
+	fromOid, _ := primitive.ObjectIDFromHex(fromUserId)
+	toOid, _ := primitive.ObjectIDFromHex(toUserId)
+	collection := s.database.Collection("leaderboards")
+
+	_, err := collection.UpdateMany(ctx, bson.M{"member_ids": fromOid}, bson.M{"$addToSet": bson.M{"member_ids": toOid}})
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "TransferLeaderboards"}
+	}
+	_, err = collection.UpdateMany(ctx, bson.M{"member_ids": fromOid}, bson.M{"$pull": bson.M{"member_ids": fromOid}})
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "TransferLeaderboards"}
+	}
+	_, err = collection.UpdateMany(ctx, bson.M{"owner_id": fromOid}, bson.M{"$set": bson.M{"owner_id": toOid}})
+	if err != nil {
+		return models.ErrRepoFailed{Message: err.Error(), RepoMethod: "TransferLeaderboards"}
+	}
+	return nil
+}
+
+func (s *Service) UpdateProfile(ctx context.Context, userId string, update models.ProfileUpdate) (*models.User, error) {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	set := bson.M{}
+	if update.DisplayName != nil {
+		set["display_name"] = *update.DisplayName
+	}
+	if update.AvatarUrl != nil {
+		set["avatar_url"] = *update.AvatarUrl
+	}
+	if update.Preferences != nil {
+		set["preferences"] = persistedPreferences{
+			TimeZone:        update.Preferences.TimeZone,
+			HardModeDefault: update.Preferences.HardModeDefault,
+			Palette:         update.Preferences.Palette,
+		}
+	}
+	if len(set) == 0 {
+		return s.findUserByOid(ctx, userOid, "UpdateProfile")
+	}
+
+	return s.updateUser(ctx, userOid, bson.M{"$set": set}, "UpdateProfile")
+}
+
+func (s *Service) findUserByOid(ctx context.Context, userOid primitive.ObjectID, repoMethod string) (*models.User, error) {
+	opts := options.FindOne().SetProjection(bson.M{"game_boards": 0, "multi_boards": 0})
+	return decodeUserResult(s.database.Collection("users").FindOne(ctx, bson.M{"_id": userOid}, opts), repoMethod)
+}
+
+func decodeUserResult(result *mongo.SingleResult, repoMethod string) (*models.User, error) {
+	if result.Err() != nil {
+		if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+			return nil, models.ErrNotFound{Message: result.Err().Error(), RepoMethod: repoMethod}
+		}
+		return nil, models.ErrRepoFailed{Message: result.Err().Error(), RepoMethod: repoMethod}
+	}
+
+	pUser := new(persistedUser)
+	if err := result.Decode(pUser); err != nil {
+		return nil, models.ErrRepoFailed{Message: err.Error(), RepoMethod: repoMethod}
 	}
 	model := persistedUserToModel(*pUser)
 	return &model, nil
 }
 
-func (s *Service) InsertUser(ctx context.Context, user models.NewUser) (*models.User, error) {
-	col := s.database.Collection("users")
-	persist := &persistedUser{
-		DisplayName: user.DisplayName,
-		OauthUuid:   user.ID,
-		GameBoards:  make([]persistedGameBoard, 0),
+func (s *Service) SetAdmin(ctx context.Context, userId string, admin bool) (*models.User, error) {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	update := bson.M{"$set": bson.M{"admin": admin}}
+	if !admin {
+		update = bson.M{"$unset": bson.M{"admin": ""}}
 	}
-	result, err := col.InsertOne(ctx, persist)
-	if err != nil {
-		return nil, models.ErrRepoFailed{
-			Message:    err.Error(),
-			RepoMethod: "InsertUser",
+	return s.updateUser(ctx, userOid, update, "SetAdmin")
+}
+
+// SetBan bans a user, or lifts their ban when bannedAt is nil
+func (s *Service) SetBan(ctx context.Context, userId string, bannedAt *time.Time, reason *string) (*models.User, error) {
+	userOid, _ := primitive.ObjectIDFromHex(userId)
+	update := bson.M{"$unset": bson.M{"banned_at": "", "ban_reason": ""}}
+	if bannedAt != nil {
+		set := bson.M{"banned_at": *bannedAt}
+		unset := bson.M{}
+		if reason != nil {
+			set["ban_reason"] = *reason
+		} else {
+			unset["ban_reason"] = ""
+		}
+		update = bson.M{"$set": set}
+		if len(unset) > 0 {
+			update["$unset"] = unset
 		}
 	}
-	return &models.User{
-		ID:          result.InsertedID.(primitive.ObjectID).Hex(),
-		DisplayName: user.DisplayName,
-		OauthId:     user.ID,
-	}, nil
+	return s.updateUser(ctx, userOid, update, "SetBan")
+}
+
+func (s *Service) updateUser(ctx context.Context, userOid primitive.ObjectID, update bson.M, repoMethod string) (*models.User, error) {
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"game_boards": 0, "multi_boards": 0})
+	result := s.database.Collection("users").FindOneAndUpdate(ctx, bson.M{"_id": userOid}, update, opts)
+	return decodeUserResult(result, repoMethod)
 }
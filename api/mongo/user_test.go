@@ -0,0 +1,80 @@
+package mongo
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"sync"
+	"testing"
+)
+
+// racingUsers holds at most one user, like the users collection does for a uid with its unique
+// indexes. The first upserts all miss before any of them inserts, the way concurrent upserts race.
+type racingUsers struct {
+	mu      sync.Mutex
+	missed  sync.WaitGroup
+	misses  int
+	racing  int
+	exists  bool
+	inserts int
+}
+
+func (c *racingUsers) UpdateOne(_ context.Context, _ interface{}, _ interface{}, _ ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	c.mu.Lock()
+	if c.exists {
+		c.mu.Unlock()
+		return &mongo.UpdateResult{MatchedCount: 1}, nil
+	}
+	c.misses++
+	waits := c.misses <= c.racing
+	c.mu.Unlock()
+	if waits {
+		c.missed.Done()
+		c.missed.Wait()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.exists {
+		return nil, mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "duplicate key"}}}
+	}
+	c.exists = true
+	c.inserts++
+	return &mongo.UpdateResult{UpsertedCount: 1}, nil
+}
+
+func TestUpsertOnceConcurrently(t *testing.T) {
+	const calls = 20
+	collection := &racingUsers{racing: calls}
+	collection.missed.Add(calls)
+
+	var wg sync.WaitGroup
+	results := make([]*mongo.UpdateResult, calls)
+	errs := make([]error, calls)
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = upsertOnce(context.Background(), collection, nil, nil)
+		}(i)
+	}
+	wg.Wait()
+
+	upserted := 0
+	for i := 0; i < calls; i++ {
+		if errs[i] != nil {
+			t.Fatalf("call %d failed: %v", i, errs[i])
+		}
+		if results[i].UpsertedCount > 0 {
+			upserted++
+		} else if results[i].MatchedCount != 1 {
+			t.Errorf("call %d neither inserted nor matched the user", i)
+		}
+	}
+	if upserted != 1 {
+		t.Errorf("%d calls inserted the user, want 1", upserted)
+	}
+	if collection.inserts != 1 {
+		t.Errorf("the user was inserted %d times, want 1", collection.inserts)
+	}
+}
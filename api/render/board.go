@@ -0,0 +1,81 @@
+package render
+
+import (
+	"github.com/amanzanero/wordleboard/api/models"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+)
+
+const (
+	tileSize   = 60
+	tileGap    = 8
+	padding    = 32
+	titleScale = 4
+)
+
+var (
+	background = color.RGBA{R: 0x12, G: 0x12, B: 0x13, A: 0xff}
+	foreground = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	muted      = color.RGBA{R: 0x81, G: 0x83, B: 0x84, A: 0xff}
+)
+
+// paletteColors are the tile colors for incorrect, in word, and in location letters
+var paletteColors = map[models.SharePalette][3]color.RGBA{
+	models.SharePaletteDefault: {
+		{R: 0x3a, G: 0x3a, B: 0x3c, A: 0xff},
+		{R: 0xb5, G: 0x9f, B: 0x3b, A: 0xff},
+		{R: 0x53, G: 0x8d, B: 0x4e, A: 0xff},
+	},
+	models.SharePaletteLight: {
+		{R: 0x78, G: 0x7c, B: 0x7e, A: 0xff},
+		{R: 0xc9, G: 0xb4, B: 0x58, A: 0xff},
+		{R: 0x6a, G: 0xaa, B: 0x64, A: 0xff},
+	},
+	models.SharePaletteHighContrast: {
+		{R: 0x3a, G: 0x3a, B: 0x3c, A: 0xff},
+		{R: 0x85, G: 0xc0, B: 0xf9, A: 0xff},
+		{R: 0xf5, G: 0x79, B: 0x3a, A: 0xff},
+	},
+}
+
+// BoardPNG draws a finished board as a card with a title above its grid. Only the colors of each
+// guess are drawn, never the letters, so the card can't spoil the solution.
+func BoardPNG(w io.Writer, title string, guesses [][]models.GuessState, palette models.SharePalette) error {
+	colors, ok := paletteColors[palette]
+	if !ok {
+		colors = paletteColors[models.SharePaletteDefault]
+	}
+
+	gridWidth := 5*tileSize + 4*tileGap
+	titleHeight := glyphHeight * titleScale
+	width := gridWidth + 2*padding
+	if titleWidth := textWidth(title, titleScale) + 2*padding; titleWidth > width {
+		width = titleWidth
+	}
+	height := padding + titleHeight + padding + len(guesses)*(tileSize+tileGap) - tileGap + padding
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	drawText(img, title, (width-textWidth(title, titleScale))/2, padding, titleScale, foreground)
+
+	left := (width - gridWidth) / 2
+	top := padding + titleHeight + padding
+	for row, states := range guesses {
+		for col, state := range states {
+			tile := colors[0]
+			switch state.Guess {
+			case models.LetterGuessInLocation:
+				tile = colors[2]
+			case models.LetterGuessInWord:
+				tile = colors[1]
+			}
+			x := left + col*(tileSize+tileGap)
+			y := top + row*(tileSize+tileGap)
+			draw.Draw(img, image.Rect(x, y, x+tileSize, y+tileSize), image.NewUniform(tile), image.Point{}, draw.Src)
+		}
+	}
+	return png.Encode(w, img)
+}
@@ -0,0 +1,105 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+)
+
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+// glyphs is a small 5x7 bitmap font, so images can be drawn without any font files. Lowercase
+// letters are drawn as uppercase, and anything missing is drawn as a question mark.
+var glyphs = map[rune][glyphHeight]string{
+	'A':  {".###.", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'B':  {"####.", "#...#", "#...#", "####.", "#...#", "#...#", "####."},
+	'C':  {".###.", "#...#", "#....", "#....", "#....", "#...#", ".###."},
+	'D':  {"####.", "#...#", "#...#", "#...#", "#...#", "#...#", "####."},
+	'E':  {"#####", "#....", "#....", "####.", "#....", "#....", "#####"},
+	'F':  {"#####", "#....", "#....", "####.", "#....", "#....", "#...."},
+	'G':  {".###.", "#...#", "#....", "#.###", "#...#", "#...#", ".####"},
+	'H':  {"#...#", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'I':  {".###.", "..#..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'J':  {"..###", "...#.", "...#.", "...#.", "...#.", "#..#.", ".##.."},
+	'K':  {"#...#", "#..#.", "#.#..", "##...", "#.#..", "#..#.", "#...#"},
+	'L':  {"#....", "#....", "#....", "#....", "#....", "#....", "#####"},
+	'M':  {"#...#", "##.##", "#.#.#", "#.#.#", "#...#", "#...#", "#...#"},
+	'N':  {"#...#", "#...#", "##..#", "#.#.#", "#..##", "#...#", "#...#"},
+	'O':  {".###.", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'P':  {"####.", "#...#", "#...#", "####.", "#....", "#....", "#...."},
+	'Q':  {".###.", "#...#", "#...#", "#...#", "#.#.#", "#..#.", ".##.#"},
+	'R':  {"####.", "#...#", "#...#", "####.", "#.#..", "#..#.", "#...#"},
+	'S':  {".####", "#....", "#....", ".###.", "....#", "....#", "####."},
+	'T':  {"#####", "..#..", "..#..", "..#..", "..#..", "..#..", "..#.."},
+	'U':  {"#...#", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'V':  {"#...#", "#...#", "#...#", "#...#", "#...#", ".#.#.", "..#.."},
+	'W':  {"#...#", "#...#", "#...#", "#.#.#", "#.#.#", "#.#.#", ".#.#."},
+	'X':  {"#...#", "#...#", ".#.#.", "..#..", ".#.#.", "#...#", "#...#"},
+	'Y':  {"#...#", "#...#", ".#.#.", "..#..", "..#..", "..#..", "..#.."},
+	'Z':  {"#####", "....#", "...#.", "..#..", ".#...", "#....", "#####"},
+	'0':  {".###.", "#...#", "#..##", "#.#.#", "##..#", "#...#", ".###."},
+	'1':  {"..#..", ".##..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'2':  {".###.", "#...#", "....#", "...#.", "..#..", ".#...", "#####"},
+	'3':  {"#####", "...#.", "..#..", "...#.", "....#", "#...#", ".###."},
+	'4':  {"...#.", "..##.", ".#.#.", "#..#.", "#####", "...#.", "...#."},
+	'5':  {"#####", "#....", "####.", "....#", "....#", "#...#", ".###."},
+	'6':  {"..##.", ".#...", "#....", "####.", "#...#", "#...#", ".###."},
+	'7':  {"#####", "....#", "...#.", "..#..", ".#...", ".#...", ".#..."},
+	'8':  {".###.", "#...#", "#...#", ".###.", "#...#", "#...#", ".###."},
+	'9':  {".###.", "#...#", "#...#", ".####", "....#", "...#.", ".##.."},
+	' ':  {".....", ".....", ".....", ".....", ".....", ".....", "....."},
+	'.':  {".....", ".....", ".....", ".....", ".....", ".##..", ".##.."},
+	'/':  {".....", "....#", "...#.", "..#..", ".#...", "#....", "....."},
+	'*':  {".....", "..#..", "#.#.#", ".###.", "#.#.#", "..#..", "....."},
+	'#':  {".#.#.", ".#.#.", "#####", ".#.#.", "#####", ".#.#.", ".#.#."},
+	'-':  {".....", ".....", ".....", "#####", ".....", ".....", "....."},
+	'_':  {".....", ".....", ".....", ".....", ".....", ".....", "#####"},
+	':':  {".....", ".##..", ".##..", ".....", ".##..", ".##..", "....."},
+	'!':  {"..#..", "..#..", "..#..", "..#..", "..#..", ".....", "..#.."},
+	'?':  {".###.", "#...#", "....#", "...#.", "..#..", ".....", "..#.."},
+	'\'': {"..#..", "..#..", ".#...", ".....", ".....", ".....", "....."},
+}
+
+// textWidth is how many pixels wide text is when drawn at a scale
+func textWidth(text string, scale int) int {
+	runes := len([]rune(text))
+	if runes == 0 {
+		return 0
+	}
+	return (runes*(glyphWidth+1) - 1) * scale
+}
+
+// drawText draws text with its top left corner at x, y. Every pixel of a glyph is drawn as a
+// scale by scale square.
+func drawText(img draw.Image, text string, x, y, scale int, c color.Color) {
+	fill := image.NewUniform(c)
+	for _, r := range strings.ToUpper(text) {
+		glyph, ok := glyphs[r]
+		if !ok {
+			glyph = glyphs['?']
+		}
+		for row, line := range glyph {
+			for col, pixel := range line {
+				if pixel != '#' {
+					continue
+				}
+				rect := image.Rect(x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale)
+				draw.Draw(img, rect, fill, image.Point{}, draw.Src)
+			}
+		}
+		x += (glyphWidth + 1) * scale
+	}
+}
+
+// truncate shortens text to at most max characters, marking that it was cut
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max-2]) + ".."
+}
@@ -0,0 +1,51 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+)
+
+const (
+	inviteWidth  = 1200
+	inviteHeight = 630
+	inviteScale  = 8
+	detailScale  = 5
+)
+
+// InvitePNG draws the preview card for a leaderboard invite link, sized for OpenGraph images
+func InvitePNG(w io.Writer, name string, memberCount int, ownerName string) error {
+	img := image.NewRGBA(image.Rect(0, 0, inviteWidth, inviteHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+
+	members := fmt.Sprintf("%d members", memberCount)
+	if memberCount == 1 {
+		members = "1 member"
+	}
+	lines := []struct {
+		text  string
+		scale int
+		color color.Color
+	}{
+		{"join the leaderboard", detailScale, muted},
+		{truncate(name, 20), inviteScale, foreground},
+		{members, detailScale, foreground},
+		{truncate("owned by "+ownerName, 28), detailScale, muted},
+	}
+
+	lineGap := 40
+	height := -lineGap
+	for _, line := range lines {
+		height += glyphHeight*line.scale + lineGap
+	}
+	y := (inviteHeight - height) / 2
+	for _, line := range lines {
+		x := (inviteWidth - textWidth(line.text, line.scale)) / 2
+		drawText(img, line.text, x, y, line.scale, line.color)
+		y += glyphHeight*line.scale + lineGap
+	}
+	return png.Encode(w, img)
+}
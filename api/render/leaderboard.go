@@ -0,0 +1,55 @@
+package render
+
+import (
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+)
+
+const (
+	tableScale   = 3
+	rowHeight    = glyphHeight*tableScale + 16
+	nameColumns  = 16
+	maxStandings = 10
+)
+
+// StandingsPNG draws a leaderboard's top standings as a table of rank, name, average guesses, and
+// games won
+func StandingsPNG(w io.Writer, title string, standings []*models.Standing) error {
+	if len(standings) > maxStandings {
+		standings = standings[:maxStandings]
+	}
+
+	header := fmt.Sprintf("%-3s %-*s %5s %4s", "#", nameColumns, "NAME", "AVG", "WON")
+	width := textWidth(header, tableScale) + 2*padding
+	if titleWidth := textWidth(truncate(title, 24), titleScale) + 2*padding; titleWidth > width {
+		width = titleWidth
+	}
+	titleHeight := glyphHeight * titleScale
+	height := padding + titleHeight + padding + (len(standings)+1)*rowHeight + padding
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	drawText(img, truncate(title, 24), padding, padding, titleScale, foreground)
+
+	top := padding + titleHeight + padding
+	drawText(img, header, padding, top, tableScale, muted)
+	for i, standing := range standings {
+		average := "-"
+		if standing.AverageGuesses != nil {
+			average = fmt.Sprintf("%.2f", *standing.AverageGuesses)
+		}
+		line := fmt.Sprintf("%-3d %-*s %5s %4d",
+			standing.Rank,
+			nameColumns,
+			truncate(standing.User.DisplayName, nameColumns),
+			average,
+			standing.GamesWon,
+		)
+		drawText(img, line, padding, top+(i+1)*rowHeight, tableScale, foreground)
+	}
+	return png.Encode(w, img)
+}
@@ -9,6 +9,21 @@ var (
 	firebaseEndpoint    = "https://www.googleapis.com/identitytoolkit/v3/relyingparty/verifyCustomToken?key="
 	port                = os.Getenv("PORT")
 	mongoUri            = os.Getenv("MONGO_URI")
+
+	// these choose how users sign in, firebase unless AUTH_PROVIDER is "local"
+	authProvider = os.Getenv("AUTH_PROVIDER")
+	localJWTKey  = os.Getenv("LOCAL_JWT_KEY")
+
+	// first-party accounts need LOCAL_JWT_KEY to sign sessions. PUBLIC_URL is the site's origin,
+	// emailed links and leaderboard invite previews link to it. Emails are written to EMAIL_OUTBOX if it's set, or stdout if it isn't.
+	publicUrl   = os.Getenv("PUBLIC_URL")
+	emailOutbox = os.Getenv("EMAIL_OUTBOX")
+
+	// uploads like avatars are kept in BLOB_DIR, and can't be uploaded if it isn't set
+	blobDir = os.Getenv("BLOB_DIR")
+
+	// users signed in with any of the comma separated ADMIN_UIDS are made admins when the server starts
+	adminUids = os.Getenv("ADMIN_UIDS")
 )
 
 const (
@@ -22,4 +37,10 @@ const (
 	FirebaseEndpoint
 	Port
 	MongoUri
+	AuthProvider
+	LocalJWTKey
+	PublicUrl
+	EmailOutbox
+	BlobDir
+	AdminUids
 )
@@ -51,6 +51,12 @@ func (m *Manager) Initialize() {
 		m.secretsCache[MongoUri] = b.String()
 	}
 	m.secretsCache[Port] = port
+	m.secretsCache[AuthProvider] = authProvider
+	m.secretsCache[LocalJWTKey] = localJWTKey
+	m.secretsCache[PublicUrl] = publicUrl
+	m.secretsCache[EmailOutbox] = emailOutbox
+	m.secretsCache[BlobDir] = blobDir
+	m.secretsCache[AdminUids] = adminUids
 }
 
 func (m *Manager) GetSecretString(secret Secret) string {
@@ -0,0 +1,140 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"strings"
+	"time"
+)
+
+const (
+	// AccessTokenPrefix tells access tokens apart from provider tokens, and makes them easy to spot
+	// when they're leaked
+	AccessTokenPrefix  = "wbp_"
+	maxAccessTokens    = 25
+	maxAccessTokenName = 64
+	// last used times are only saved this often, so busy bots don't write on every request
+	accessTokenTouchInterval = time.Minute
+)
+
+var (
+	ErrInvalidAccessTokenName = fmt.Errorf("access token names must be between 1 and %d characters", maxAccessTokenName)
+	ErrNoAccessTokenScopes    = errors.New("access tokens need at least one scope")
+	ErrTooManyAccessTokens    = fmt.Errorf("users can have at most %d active access tokens", maxAccessTokens)
+	ErrGuestAccessToken       = errors.New("guests can't create access tokens")
+	ErrInvalidAccessToken     = errors.New("invalid access token")
+)
+
+var accessTokenCtxKey = &contextKey{"accessToken"}
+
+// CreateAccessToken creates a token for scripts and bots to act as the user. The token is only
+// ever returned here, only its hash is stored.
+func (s *Service) CreateAccessToken(ctx context.Context, user models.User, name string, scopes []models.TokenScope) (*models.NewAccessToken, error) {
+	if user.IsGuest() {
+		return nil, ErrGuestAccessToken
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxAccessTokenName {
+		return nil, ErrInvalidAccessTokenName
+	}
+	scopes = uniqueScopes(scopes)
+	if len(scopes) == 0 {
+		return nil, ErrNoAccessTokenScopes
+	}
+
+	existing, findErr := s.AccessTokens.FindAccessTokensForUser(ctx, user.ID)
+	if findErr != nil {
+		return nil, findErr
+	}
+	active := 0
+	for _, token := range existing {
+		if token.RevokedAt == nil {
+			active++
+		}
+	}
+	if active >= maxAccessTokens {
+		return nil, ErrTooManyAccessTokens
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	token := AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	accessToken, insertErr := s.AccessTokens.InsertAccessToken(ctx, models.AccessToken{
+		UserId:    user.ID,
+		Name:      name,
+		Hash:      hashToken(token),
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+	})
+	if insertErr != nil {
+		return nil, insertErr
+	}
+	return &models.NewAccessToken{Token: token, AccessToken: accessToken}, nil
+}
+
+func (s *Service) GetAccessTokens(ctx context.Context, user models.User) ([]*models.AccessToken, error) {
+	return s.AccessTokens.FindAccessTokensForUser(ctx, user.ID)
+}
+
+func (s *Service) RevokeAccessToken(ctx context.Context, user models.User, id string) error {
+	// the revoked token could be cached, and the cache only knows tokens by user
+	defer s.invalidateCachedUser(user.ID)
+	return s.AccessTokens.RevokeAccessToken(ctx, user.ID, id, time.Now())
+}
+
+// authenticateAccessToken finds the user an access token acts as. Revoked tokens, and tokens of
+// users that are being deleted, aren't accepted.
+func (s *Service) authenticateAccessToken(ctx context.Context, token string) (*models.User, *models.AccessToken, error) {
+	accessToken, findErr := s.AccessTokens.FindAccessTokenByHash(ctx, hashToken(token))
+	if _, isNotFound := findErr.(models.ErrNotFound); isNotFound {
+		return nil, nil, ErrInvalidAccessToken
+	} else if findErr != nil {
+		return nil, nil, findErr
+	}
+	if accessToken.RevokedAt != nil {
+		return nil, nil, ErrInvalidAccessToken
+	}
+
+	user, userErr := s.Repo.FindUserById(ctx, accessToken.UserId)
+	if _, isNotFound := userErr.(models.ErrNotFound); isNotFound {
+		return nil, nil, ErrInvalidAccessToken
+	} else if userErr != nil {
+		return nil, nil, userErr
+	}
+	if user.DeletedAt != nil {
+		return nil, nil, ErrInvalidAccessToken
+	}
+
+	now := time.Now()
+	if accessToken.LastUsedAt == nil || now.Sub(*accessToken.LastUsedAt) > accessTokenTouchInterval {
+		if err := s.AccessTokens.TouchAccessToken(ctx, accessToken.ID, now); err != nil {
+			s.Logger.Errorf("failed to save when access token %s was used: %v", accessToken.ID, err)
+		}
+	}
+	return user, accessToken, nil
+}
+
+// AccessTokenForContext finds the access token a request was made with. It's nil when the user
+// signed in with an identity provider, and can do anything.
+func AccessTokenForContext(ctx context.Context) *models.AccessToken {
+	raw, _ := ctx.Value(accessTokenCtxKey).(*models.AccessToken)
+	return raw
+}
+
+func uniqueScopes(scopes []models.TokenScope) []models.TokenScope {
+	seen := make(map[models.TokenScope]bool)
+	unique := make([]models.TokenScope, 0, len(scopes))
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope)
+		}
+	}
+	return unique
+}
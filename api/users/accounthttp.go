@@ -0,0 +1,162 @@
+package users
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type sessionResponse struct {
+	Token string `json:"token"`
+}
+
+// RegisterHandler emails a link that creates a first-party account. It always responds the same
+// way, whether or not the email has an account.
+func (s *Service) RegisterHandler() http.HandlerFunc {
+	type requestParams struct {
+		Email       string `json:"email"`
+		Password    string `json:"password"`
+		DisplayName string `json:"display_name"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := new(requestParams)
+		if !decodeParams(w, r, params) {
+			return
+		}
+		if !s.allowAccountRequest(w, r, params.Email) {
+			return
+		}
+		err := s.Register(r.Context(), params.Email, params.Password, params.DisplayName)
+		s.respondWithAccepted(w, r, err)
+	}
+}
+
+// LoginHandler signs in with an email and password and responds with a session token
+func (s *Service) LoginHandler() http.HandlerFunc {
+	type requestParams struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := new(requestParams)
+		if !decodeParams(w, r, params) {
+			return
+		}
+		if !s.allowAccountRequest(w, r, params.Email) {
+			return
+		}
+		token, err := s.Login(r.Context(), params.Email, params.Password)
+		s.respondWithSession(w, r, token, err)
+	}
+}
+
+// PasswordResetHandler emails a password reset link. It always responds the same way, whether or
+// not the email has an account.
+func (s *Service) PasswordResetHandler() http.HandlerFunc {
+	type requestParams struct {
+		Email string `json:"email"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := new(requestParams)
+		if !decodeParams(w, r, params) {
+			return
+		}
+		if !s.allowAccountRequest(w, r, params.Email) {
+			return
+		}
+		err := s.RequestPasswordReset(r.Context(), params.Email)
+		s.respondWithAccepted(w, r, err)
+	}
+}
+
+// ConfirmPasswordResetHandler sets a new password with the token from a reset email
+func (s *Service) ConfirmPasswordResetHandler() http.HandlerFunc {
+	type requestParams struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := new(requestParams)
+		if !decodeParams(w, r, params) {
+			return
+		}
+		if !s.allowAccountRequest(w, r, "") {
+			return
+		}
+		token, err := s.ResetPassword(r.Context(), params.Token, params.Password)
+		s.respondWithSession(w, r, token, err)
+	}
+}
+
+// MagicLinkHandler emails a link that signs in without a password
+func (s *Service) MagicLinkHandler() http.HandlerFunc {
+	type requestParams struct {
+		Email string `json:"email"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := new(requestParams)
+		if !decodeParams(w, r, params) {
+			return
+		}
+		if !s.allowAccountRequest(w, r, params.Email) {
+			return
+		}
+		err := s.RequestMagicLink(r.Context(), params.Email)
+		s.respondWithAccepted(w, r, err)
+	}
+}
+
+// ConfirmMagicLinkHandler signs in with the token from a magic link or registration email
+func (s *Service) ConfirmMagicLinkHandler() http.HandlerFunc {
+	type requestParams struct {
+		Token string `json:"token"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := new(requestParams)
+		if !decodeParams(w, r, params) {
+			return
+		}
+		if !s.allowAccountRequest(w, r, "") {
+			return
+		}
+		token, err := s.ConsumeMagicLink(r.Context(), params.Token)
+		s.respondWithSession(w, r, token, err)
+	}
+}
+
+func decodeParams(w http.ResponseWriter, r *http.Request, params interface{}) bool {
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid input")
+		return false
+	}
+	return true
+}
+
+func (s *Service) respondWithSession(w http.ResponseWriter, r *http.Request, token string, err error) {
+	if err != nil {
+		s.respondWithAccountError(w, r, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, sessionResponse{Token: token})
+}
+
+func (s *Service) respondWithAccepted(w http.ResponseWriter, r *http.Request, err error) {
+	if err != nil {
+		s.respondWithAccountError(w, r, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusAccepted, map[string]string{"msg": "accepted"})
+}
+
+func (s *Service) respondWithAccountError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case ErrInvalidEmail, ErrWeakPassword, ErrInvalidDisplayName, ErrInappropriateDisplayName, ErrInvalidLoginToken:
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	case ErrInvalidCredentials:
+		respondWithError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	s.Logger.Errorf("account request to %s failed: %v", r.URL.Path, err)
+	respondWithError(w, http.StatusInternalServerError, "internal error")
+}
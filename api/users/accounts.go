@@ -0,0 +1,358 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"github.com/lithammer/shortuuid/v4"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// accountUidPrefix sets account uids apart from the uids of other identity providers
+	accountUidPrefix = "account:"
+
+	sessionTTL        = 30 * 24 * time.Hour
+	passwordResetTTL  = time.Hour
+	magicLinkTTL      = 15 * time.Minute
+	minPasswordLength = 10
+	maxPasswordLength = 256
+	maxDisplayName    = 32
+)
+
+var (
+	ErrInvalidEmail             = errors.New("invalid email address")
+	ErrWeakPassword             = fmt.Errorf("passwords must be between %d and %d characters", minPasswordLength, maxPasswordLength)
+	ErrInvalidDisplayName       = fmt.Errorf("display names must be between 1 and %d characters", maxDisplayName)
+	ErrInappropriateDisplayName = errors.New("display names can't have inappropriate words")
+	ErrInvalidCredentials       = errors.New("invalid email or password")
+	ErrInvalidLoginToken        = errors.New("this link is invalid or has expired")
+)
+
+// AccountProvider is the identity provider for first-party accounts. Accounts sign in with
+// session tokens signed by Sessions.
+type AccountProvider struct {
+	Repo     models.AccountRepo
+	Sessions *LocalJWTProvider
+}
+
+// VerifyToken checks a session token, and that it wasn't issued before the account's password was
+// last reset
+func (p AccountProvider) VerifyToken(ctx context.Context, token string) (string, error) {
+	claims, err := p.Sessions.verifyClaims(token)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(claims.Subject, accountUidPrefix) {
+		return "", errors.New("token isn't for an account")
+	}
+	account, findErr := p.Repo.FindAccountByUid(ctx, claims.Subject)
+	if findErr != nil {
+		return "", rejectedError{findErr}
+	}
+	if account.SessionsNotBefore != nil && time.Unix(claims.IssuedAt, 0).Before(*account.SessionsNotBefore) {
+		return "", rejectedError{errors.New("session was signed out")}
+	}
+	return claims.Subject, nil
+}
+
+func (p AccountProvider) GetIdentity(ctx context.Context, uid string) (*Identity, error) {
+	if !strings.HasPrefix(uid, accountUidPrefix) {
+		return nil, models.ErrNotFound{Message: fmt.Sprintf("%s isn't an account uid", uid), RepoMethod: "GetIdentity"}
+	}
+	account, err := p.Repo.FindAccountByUid(ctx, uid)
+	if err != nil {
+		return nil, rejectedError{err}
+	}
+	return &Identity{UID: account.UID, DisplayName: account.DisplayName, Email: account.Email}, nil
+}
+
+func (p AccountProvider) CustomToken(_ context.Context, uid string) (string, error) {
+	return p.Sessions.issue(uid, sessionTTL)
+}
+
+// Register emails a link that creates an account with a password and signs it in. The account
+// isn't created until the link is used, so nobody can register an email they don't own. If the
+// email already has an account, its owner is told instead, and the caller can't tell the difference.
+func (s *Service) Register(ctx context.Context, email, password, displayName string) error {
+	email, emailErr := normalizeEmail(email)
+	if emailErr != nil {
+		return emailErr
+	}
+	if err := validatePassword(password); err != nil {
+		return err
+	}
+	displayName, profileErr := validateDisplayName(displayName)
+	if profileErr != nil {
+		if profileErr.Error == models.ProfileErrorInappropriateDisplayName {
+			return ErrInappropriateDisplayName
+		}
+		return ErrInvalidDisplayName
+	}
+
+	// the password is hashed either way, so how long this takes doesn't give away who has an account
+	passwordHash, hashErr := hashPassword(password)
+	if hashErr != nil {
+		return hashErr
+	}
+	account, findErr := s.Accounts.FindAccountByEmail(ctx, email)
+	if findErr == nil {
+		return s.Email.Send(ctx, Email{
+			To:      account.Email,
+			Subject: "You already have a wordleboard account",
+			Body: fmt.Sprintf(
+				"Someone tried to create a wordleboard account with this email, but you already have one. If it was you, sign in or reset your password at:\n\n%s/login\n\nIf it wasn't you, you can ignore this email.",
+				s.PublicUrl,
+			),
+		})
+	} else if _, isNotFound := findErr.(models.ErrNotFound); !isNotFound {
+		return findErr
+	}
+
+	token, tokenErr := s.createLoginToken(ctx, models.LoginToken{
+		Purpose:      models.LoginTokenPurposeMagicLink,
+		Email:        email,
+		DisplayName:  displayName,
+		PasswordHash: passwordHash,
+	}, magicLinkTTL)
+	if tokenErr != nil {
+		return tokenErr
+	}
+	return s.Email.Send(ctx, Email{
+		To:      email,
+		Subject: "Finish creating your wordleboard account",
+		Body: fmt.Sprintf(
+			"Use this link within 15 minutes to finish creating your wordleboard account:\n\n%s/login/magic?token=%s\n\nIf you didn't ask for an account, you can ignore this email.",
+			s.PublicUrl,
+			token,
+		),
+	})
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+// Login signs an account in with its password
+func (s *Service) Login(ctx context.Context, email, password string) (string, error) {
+	email, emailErr := normalizeEmail(email)
+	if emailErr != nil {
+		return "", ErrInvalidCredentials
+	}
+
+	account, findErr := s.Accounts.FindAccountByEmail(ctx, email)
+	if _, isNotFound := findErr.(models.ErrNotFound); findErr != nil && !isNotFound {
+		return "", findErr
+	}
+	if account == nil || account.PasswordHash == "" {
+		// hash anyway, so how long this takes doesn't give away which emails have accounts
+		dummyHashOnce.Do(func() {
+			dummyHash, _ = hashPassword("not a real password")
+		})
+		_, _ = verifyPassword(password, dummyHash)
+		return "", ErrInvalidCredentials
+	}
+
+	matches, verifyErr := verifyPassword(password, account.PasswordHash)
+	if verifyErr != nil {
+		return "", verifyErr
+	}
+	if !matches {
+		return "", ErrInvalidCredentials
+	}
+	return s.Sessions.issue(account.UID, sessionTTL)
+}
+
+// RequestPasswordReset emails a password reset link. Nothing is sent if there's no account for the
+// email, but the caller isn't told, so this can't be used to find out who has an account.
+func (s *Service) RequestPasswordReset(ctx context.Context, email string) error {
+	email, emailErr := normalizeEmail(email)
+	if emailErr != nil {
+		return emailErr
+	}
+	account, findErr := s.Accounts.FindAccountByEmail(ctx, email)
+	if _, isNotFound := findErr.(models.ErrNotFound); isNotFound {
+		return nil
+	} else if findErr != nil {
+		return findErr
+	}
+
+	token, tokenErr := s.createLoginToken(ctx, models.LoginToken{
+		Purpose:    models.LoginTokenPurposePasswordReset,
+		AccountUid: account.UID,
+		Email:      account.Email,
+	}, passwordResetTTL)
+	if tokenErr != nil {
+		return tokenErr
+	}
+	return s.Email.Send(ctx, Email{
+		To:      account.Email,
+		Subject: "Reset your wordleboard password",
+		Body: fmt.Sprintf(
+			"Someone asked to reset the password for your wordleboard account. If it was you, use this link within the hour:\n\n%s/login/reset?token=%s\n\nIf it wasn't you, you can ignore this email.",
+			s.PublicUrl,
+			token,
+		),
+	})
+}
+
+// ResetPassword sets a new password with a token from a reset email, and signs the account in. Every
+// other session of the account is signed out.
+func (s *Service) ResetPassword(ctx context.Context, token, password string) (string, error) {
+	if err := validatePassword(password); err != nil {
+		return "", err
+	}
+	loginToken, consumeErr := s.Accounts.ConsumeLoginToken(ctx, hashToken(token), models.LoginTokenPurposePasswordReset, time.Now())
+	if _, isNotFound := consumeErr.(models.ErrNotFound); isNotFound {
+		return "", ErrInvalidLoginToken
+	} else if consumeErr != nil {
+		return "", consumeErr
+	}
+
+	passwordHash, hashErr := hashPassword(password)
+	if hashErr != nil {
+		return "", hashErr
+	}
+	// tokens only count whole seconds, so sessions issued from the start of this second stay valid
+	notBefore := time.Now().Truncate(time.Second)
+	if err := s.Accounts.UpdateAccountPassword(ctx, loginToken.AccountUid, passwordHash, notBefore); err != nil {
+		return "", err
+	}
+	if user, findErr := s.Repo.FindUserByUuid(ctx, loginToken.AccountUid); findErr == nil {
+		s.invalidateCachedUser(user.ID)
+	}
+	return s.Sessions.issue(loginToken.AccountUid, sessionTTL)
+}
+
+// RequestMagicLink emails a link that signs in without a password. For emails that don't have an
+// account yet, one without a password is created once the link is used.
+func (s *Service) RequestMagicLink(ctx context.Context, email string) error {
+	email, emailErr := normalizeEmail(email)
+	if emailErr != nil {
+		return emailErr
+	}
+	loginToken := models.LoginToken{
+		Purpose:     models.LoginTokenPurposeMagicLink,
+		Email:       email,
+		DisplayName: provisionedDisplayName(strings.SplitN(email, "@", 2)[0]),
+	}
+	account, findErr := s.Accounts.FindAccountByEmail(ctx, email)
+	if findErr == nil {
+		loginToken.AccountUid = account.UID
+	} else if _, isNotFound := findErr.(models.ErrNotFound); !isNotFound {
+		return findErr
+	}
+
+	token, tokenErr := s.createLoginToken(ctx, loginToken, magicLinkTTL)
+	if tokenErr != nil {
+		return tokenErr
+	}
+	return s.Email.Send(ctx, Email{
+		To:      email,
+		Subject: "Sign in to wordleboard",
+		Body: fmt.Sprintf(
+			"Use this link within 15 minutes to sign in to wordleboard:\n\n%s/login/magic?token=%s\n\nIf you didn't ask to sign in, you can ignore this email.",
+			s.PublicUrl,
+			token,
+		),
+	})
+}
+
+// ConsumeMagicLink signs an account in with a token from a magic link or registration email,
+// creating the account if the email doesn't have one yet
+func (s *Service) ConsumeMagicLink(ctx context.Context, token string) (string, error) {
+	loginToken, consumeErr := s.Accounts.ConsumeLoginToken(ctx, hashToken(token), models.LoginTokenPurposeMagicLink, time.Now())
+	if _, isNotFound := consumeErr.(models.ErrNotFound); isNotFound {
+		return "", ErrInvalidLoginToken
+	} else if consumeErr != nil {
+		return "", consumeErr
+	}
+
+	uid := loginToken.AccountUid
+	if uid == "" {
+		account, err := s.findOrCreateAccount(ctx, *loginToken)
+		if err != nil {
+			return "", err
+		}
+		uid = account.UID
+	}
+	return s.Sessions.issue(uid, sessionTTL)
+}
+
+// findOrCreateAccount finds the account for a login token's email, or creates it from the token.
+// The link proves the email is owned, so an account created since the token was emailed is signed
+// in to as is.
+func (s *Service) findOrCreateAccount(ctx context.Context, loginToken models.LoginToken) (*models.Account, error) {
+	account, findErr := s.Accounts.FindAccountByEmail(ctx, loginToken.Email)
+	if _, isNotFound := findErr.(models.ErrNotFound); !isNotFound {
+		return account, findErr
+	}
+	account, insertErr := s.Accounts.InsertAccount(ctx, models.Account{
+		UID:          accountUidPrefix + shortuuid.New(),
+		Email:        loginToken.Email,
+		DisplayName:  loginToken.DisplayName,
+		PasswordHash: loginToken.PasswordHash,
+		CreatedAt:    time.Now().UTC(),
+	})
+	if _, alreadyExists := insertErr.(models.ErrAlreadyExists); alreadyExists {
+		// someone else created it first
+		return s.Accounts.FindAccountByEmail(ctx, loginToken.Email)
+	}
+	return account, insertErr
+}
+
+// createLoginToken makes a single use token from a template that has everything but the hash and
+// expiry. The token itself is only ever emailed, only its hash is stored.
+func (s *Service) createLoginToken(ctx context.Context, loginToken models.LoginToken, ttl time.Duration) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	loginToken.Hash = hashToken(token)
+	loginToken.ExpiresAt = time.Now().Add(ttl)
+	if insertErr := s.Accounts.InsertLoginToken(ctx, loginToken); insertErr != nil {
+		return "", insertErr
+	}
+	return token, nil
+}
+
+// hashToken hashes tokens that are looked up by value. They have enough entropy that a fast hash is
+// fine.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return "", ErrInvalidEmail
+	}
+	return email, nil
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return ErrWeakPassword
+	}
+	return nil
+}
+
+func truncateDisplayName(name string) string {
+	runes := []rune(name)
+	if len(runes) > maxDisplayName {
+		return string(runes[:maxDisplayName])
+	}
+	return name
+}
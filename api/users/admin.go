@@ -0,0 +1,81 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"strings"
+	"time"
+)
+
+const maxBanReason = 500
+
+var (
+	ErrCannotBanAdmin   = errors.New("admins can't be banned, remove their admin role first")
+	ErrCannotDemoteSelf = errors.New("admins can't remove their own admin role")
+	ErrInvalidBanReason = fmt.Errorf("ban reasons can be at most %d characters", maxBanReason)
+)
+
+// BanUser keeps a user from signing in until they're unbanned. Their data is left as it is.
+func (s *Service) BanUser(ctx context.Context, userId string, reason *string) (*models.User, error) {
+	user, findErr := s.Repo.FindUserById(ctx, userId)
+	if findErr != nil {
+		return nil, findErr
+	}
+	if user.IsAdmin {
+		return nil, ErrCannotBanAdmin
+	}
+	if reason != nil {
+		trimmed := strings.TrimSpace(*reason)
+		if len([]rune(trimmed)) > maxBanReason {
+			return nil, ErrInvalidBanReason
+		}
+		reason = &trimmed
+	}
+
+	now := time.Now()
+	defer s.invalidateCachedUser(userId)
+	return s.Repo.SetBan(ctx, userId, &now, reason)
+}
+
+func (s *Service) UnbanUser(ctx context.Context, userId string) (*models.User, error) {
+	defer s.invalidateCachedUser(userId)
+	return s.Repo.SetBan(ctx, userId, nil, nil)
+}
+
+// SetAdmin gives or takes away a user's admin role. Admins can't take away their own, so there's
+// always someone left to give it back.
+func (s *Service) SetAdmin(ctx context.Context, by models.User, userId string, admin bool) (*models.User, error) {
+	if by.ID == userId && !admin {
+		return nil, ErrCannotDemoteSelf
+	}
+	defer s.invalidateCachedUser(userId)
+	return s.Repo.SetAdmin(ctx, userId, admin)
+}
+
+// PromoteAdmins makes the users with the uids admins, so the first admins don't have to be set up
+// by hand. Uids without a user yet are skipped, they're promoted on a later start.
+func (s *Service) PromoteAdmins(ctx context.Context, uids []string) error {
+	for _, uid := range uids {
+		uid = strings.TrimSpace(uid)
+		if uid == "" {
+			continue
+		}
+		user, findErr := s.Repo.FindUserByUuid(ctx, uid)
+		if _, isNotFound := findErr.(models.ErrNotFound); isNotFound {
+			s.Logger.Warnf("no user with uid %s to make an admin", uid)
+			continue
+		} else if findErr != nil {
+			return findErr
+		}
+		if user.IsAdmin {
+			continue
+		}
+		if _, err := s.Repo.SetAdmin(ctx, user.ID, true); err != nil {
+			return err
+		}
+		s.Logger.Infof("made user %s an admin", user.ID)
+	}
+	return nil
+}
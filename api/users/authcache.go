@@ -0,0 +1,147 @@
+package users
+
+import (
+	"container/list"
+	"expvar"
+	"github.com/amanzanero/wordleboard/api/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+// authCacheMetrics counts hits, misses, evictions, and invalidations, and is served with the rest of
+// expvar's variables
+var authCacheMetrics = expvar.NewMap("auth_cache")
+
+func init() {
+	authCacheMetrics.Set("hit_rate", expvar.Func(func() interface{} {
+		hits, _ := authCacheMetrics.Get("hits").(*expvar.Int)
+		misses, _ := authCacheMetrics.Get("misses").(*expvar.Int)
+		if hits == nil || misses == nil || hits.Value()+misses.Value() == 0 {
+			return 0.0
+		}
+		return float64(hits.Value()) / float64(hits.Value()+misses.Value())
+	}))
+}
+
+// AuthCache remembers which user a bearer token belongs to, so repeat requests skip verifying the
+// token and looking up the user. It holds at most MaxEntries tokens, dropping the least recently
+// used, and forgets tokens after TTL. Each server has its own cache, so the TTL bounds how long
+// another server can see an outdated user.
+type AuthCache struct {
+	MaxEntries int
+	TTL        time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	recency *list.List
+	byUser  map[string]map[string]bool // user id to the keys of their tokens
+}
+
+type authCacheEntry struct {
+	key         string
+	user        models.User
+	accessToken *models.AccessToken
+	expiresAt   time.Time
+}
+
+func NewAuthCache(maxEntries int, ttl time.Duration) *AuthCache {
+	return &AuthCache{
+		MaxEntries: maxEntries,
+		TTL:        ttl,
+		entries:    make(map[string]*list.Element),
+		recency:    list.New(),
+		byUser:     make(map[string]map[string]bool),
+	}
+}
+
+// Get finds the user for a token, and the access token it is if it's one. Users are copied, so
+// callers can't change what's cached.
+func (c *AuthCache) Get(token string) (*models.User, *models.AccessToken, bool) {
+	key := hashToken(token)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, found := c.entries[key]
+	if !found {
+		authCacheMetrics.Add("misses", 1)
+		return nil, nil, false
+	}
+	entry := element.Value.(*authCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.remove(element)
+		authCacheMetrics.Add("misses", 1)
+		return nil, nil, false
+	}
+
+	c.recency.MoveToFront(element)
+	authCacheMetrics.Add("hits", 1)
+	user := entry.user
+	return &user, entry.accessToken, true
+}
+
+// Put remembers the user for a token until TTL passes or the token expires, whichever is first. A
+// zero expiry is for tokens that don't expire.
+func (c *AuthCache) Put(token string, user models.User, accessToken *models.AccessToken, tokenExpiresAt time.Time) {
+	key := hashToken(token)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := time.Now().Add(c.TTL)
+	if !tokenExpiresAt.IsZero() && tokenExpiresAt.Before(expiresAt) {
+		expiresAt = tokenExpiresAt
+	}
+
+	if element, found := c.entries[key]; found {
+		c.remove(element)
+	}
+	c.entries[key] = c.recency.PushFront(&authCacheEntry{
+		key:         key,
+		user:        user,
+		accessToken: accessToken,
+		expiresAt:   expiresAt,
+	})
+	if c.byUser[user.ID] == nil {
+		c.byUser[user.ID] = make(map[string]bool)
+	}
+	c.byUser[user.ID][key] = true
+
+	for c.recency.Len() > c.MaxEntries {
+		c.remove(c.recency.Back())
+		authCacheMetrics.Add("evictions", 1)
+	}
+}
+
+// InvalidateUser forgets every token of a user, after they change or are deleted
+func (c *AuthCache) InvalidateUser(userId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.byUser[userId] {
+		c.remove(c.entries[key])
+	}
+	authCacheMetrics.Add("invalidations", 1)
+}
+
+func (c *AuthCache) remove(element *list.Element) {
+	entry := c.recency.Remove(element).(*authCacheEntry)
+	delete(c.entries, entry.key)
+	delete(c.byUser[entry.user.ID], entry.key)
+	if len(c.byUser[entry.user.ID]) == 0 {
+		delete(c.byUser, entry.user.ID)
+	}
+}
+
+// jwtExpiry reads when a JWT expires. It doesn't check the token, so it's only used on tokens that
+// were already verified.
+func jwtExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil || claims.ExpiresAt == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.ExpiresAt, 0), true
+}
@@ -0,0 +1,102 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BlobStore keeps uploaded files, like avatars, and hands back a URL they can be loaded from
+type BlobStore interface {
+	Put(ctx context.Context, key, contentType string, contents io.Reader) (string, error)
+	Delete(ctx context.Context, key string) error
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// LocalBlobStore keeps blobs on disk, for dev and self-hosting. The server has to serve Dir at
+// BaseUrl for the URLs it hands back to work.
+type LocalBlobStore struct {
+	Dir     string
+	BaseUrl string
+}
+
+func (s LocalBlobStore) Put(_ context.Context, key, _ string, contents io.Reader) (string, error) {
+	path, pathErr := s.path(key)
+	if pathErr != nil {
+		return "", pathErr
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	file, createErr := os.Create(path)
+	if createErr != nil {
+		return "", createErr
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, contents); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(s.BaseUrl, "/") + "/" + key, nil
+}
+
+func (s LocalBlobStore) Delete(_ context.Context, key string) error {
+	path, pathErr := s.path(key)
+	if pathErr != nil {
+		return pathErr
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// DeletePrefix deletes every blob under a prefix. Prefixes are directories, so they should end with
+// a slash.
+func (s LocalBlobStore) DeletePrefix(_ context.Context, prefix string) error {
+	path, pathErr := s.path(prefix)
+	if pathErr != nil {
+		return pathErr
+	}
+	return os.RemoveAll(path)
+}
+
+// Handler serves the blobs in Dir. Only files are served, directories aren't found, so nobody can
+// list whose avatars there are.
+func (s LocalBlobStore) Handler() http.Handler {
+	return http.FileServer(filesOnly{http.Dir(s.Dir)})
+}
+
+type filesOnly struct {
+	fs http.FileSystem
+}
+
+func (f filesOnly) Open(name string) (http.File, error) {
+	file, err := f.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, statErr := file.Stat()
+	if statErr != nil {
+		file.Close()
+		return nil, statErr
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, os.ErrNotExist
+	}
+	return file, nil
+}
+
+// path keeps keys from escaping Dir
+func (s LocalBlobStore) path(key string) (string, error) {
+	path := filepath.Join(s.Dir, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, filepath.Clean(s.Dir)+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid blob key %s", key)
+	}
+	return path, nil
+}
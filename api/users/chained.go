@@ -0,0 +1,63 @@
+package users
+
+import (
+	"context"
+	"errors"
+)
+
+// ChainedProvider lets users sign in with any of several identity providers. Each is tried in
+// order, and the first one that recognizes a token or uid wins. A provider that owns a token or uid
+// but turns it down stops the chain, since a later provider sharing its key would accept it.
+type ChainedProvider []IdentityProvider
+
+// rejectedError is returned by a provider that owns a token or uid but won't accept it
+type rejectedError struct {
+	err error
+}
+
+func (e rejectedError) Error() string {
+	return e.err.Error()
+}
+
+func (e rejectedError) Unwrap() error {
+	return e.err
+}
+
+func isRejected(err error) bool {
+	var rejected rejectedError
+	return errors.As(err, &rejected)
+}
+
+func (c ChainedProvider) VerifyToken(ctx context.Context, token string) (string, error) {
+	err := errors.New("no identity providers")
+	for _, provider := range c {
+		var uid string
+		if uid, err = provider.VerifyToken(ctx, token); err == nil {
+			return uid, nil
+		} else if isRejected(err) {
+			return "", err
+		}
+	}
+	return "", err
+}
+
+func (c ChainedProvider) GetIdentity(ctx context.Context, uid string) (*Identity, error) {
+	err := errors.New("no identity providers")
+	for _, provider := range c {
+		var identity *Identity
+		if identity, err = provider.GetIdentity(ctx, uid); err == nil {
+			return identity, nil
+		} else if isRejected(err) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// CustomToken is minted by the first provider
+func (c ChainedProvider) CustomToken(ctx context.Context, uid string) (string, error) {
+	if len(c) == 0 {
+		return "", errors.New("no identity providers")
+	}
+	return c[0].CustomToken(ctx, uid)
+}
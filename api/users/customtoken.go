@@ -12,7 +12,7 @@ import (
 )
 
 func (s *Service) createCustomAuthToken(ctx context.Context, UID string) (string, error) {
-	token, err := s.Client.CustomToken(ctx, UID)
+	token, err := s.Identity.CustomToken(ctx, UID)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,72 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"time"
+)
+
+// DeleteAccount deletes a user and everything stored about them. The user is marked deleted first,
+// and every step after that can be repeated, so a deletion that fails part way is finished the next
+// time it's run, either by the user signing in again or by ResumeDeletions.
+func (s *Service) DeleteAccount(ctx context.Context, user models.User) error {
+	defer s.invalidateCachedUser(user.ID)
+	if err := s.Repo.MarkUserDeleted(ctx, user.ID, time.Now()); err != nil {
+		return err
+	}
+	if err := s.Repo.RemoveUserFromLeaderboards(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := s.Repo.DeleteUserGames(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := s.AccessTokens.DeleteAccessTokensForUser(ctx, user.ID); err != nil {
+		return err
+	}
+	if s.Accounts != nil {
+		uids, uidsErr := s.Repo.FindIdentities(ctx, user.ID)
+		if uidsErr != nil {
+			return uidsErr
+		}
+		if err := s.Accounts.DeleteAccounts(ctx, uids); err != nil {
+			return err
+		}
+	}
+	if s.Blobs != nil {
+		if err := s.Blobs.DeletePrefix(ctx, avatarPrefix(user.ID)); err != nil {
+			return err
+		}
+	}
+	// the user document goes last, it's what marks the deletion as unfinished
+	return s.Repo.DeleteUser(ctx, user.ID)
+}
+
+// ResumeDeletions finishes every deletion that stopped part way
+func (s *Service) ResumeDeletions(ctx context.Context) error {
+	deleted, err := s.Repo.FindDeletedUsers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, user := range deleted {
+		if deleteErr := s.DeleteAccount(ctx, *user); deleteErr != nil {
+			return fmt.Errorf("failed to finish deleting user %s: %w", user.ID, deleteErr)
+		}
+		s.Logger.Infof("finished deleting user %s", user.ID)
+	}
+	return nil
+}
+
+// ExportData returns everything stored about a user as a JSON archive
+func (s *Service) ExportData(ctx context.Context, user models.User) (string, error) {
+	export, err := s.Repo.ExportUserData(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+	archive, marshalErr := json.MarshalIndent(export, "", "  ")
+	if marshalErr != nil {
+		return "", marshalErr
+	}
+	return string(archive), nil
+}
@@ -0,0 +1,49 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// EmailSender delivers the emails accounts need, like password resets and magic links
+type EmailSender interface {
+	Send(ctx context.Context, email Email) error
+}
+
+// WriterEmailSender writes emails to a writer instead of sending them, which is all dev needs
+type WriterEmailSender struct {
+	W  io.Writer
+	mu sync.Mutex
+}
+
+func (s *WriterEmailSender) Send(_ context.Context, email Email) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := fmt.Fprintf(s.W, "To: %s\nSubject: %s\n\n%s\n\n", email.To, email.Subject, email.Body)
+	return err
+}
+
+// FileEmailSender writes every email to its own file in a directory
+type FileEmailSender struct {
+	Dir string
+}
+
+func (s FileEmailSender) Send(_ context.Context, email Email) error {
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d.eml", time.Now().UnixNano())
+	contents := fmt.Sprintf("To: %s\nSubject: %s\n\n%s\n", email.To, email.Subject, email.Body)
+	return os.WriteFile(filepath.Join(s.Dir, name), []byte(contents), 0o600)
+}
@@ -0,0 +1,54 @@
+package users
+
+import (
+	"context"
+	"firebase.google.com/go/v4"
+	"firebase.google.com/go/v4/auth"
+	"github.com/amanzanero/wordleboard/api/secrets"
+	"google.golang.org/api/option"
+)
+
+// FirebaseProvider signs users in with Firebase Authentication
+type FirebaseProvider struct {
+	Client *auth.Client
+}
+
+func NewFirebaseProvider(ctx context.Context, secretManager secrets.Manager) (*FirebaseProvider, error) {
+	var app *firebase.App
+	var appErr error
+	if secretManager.IsDev {
+		opt := option.WithCredentialsJSON(secretManager.GetSecretBytes(secrets.FirebaseCredentials))
+		app, appErr = firebase.NewApp(ctx, nil, opt)
+	} else {
+		app, appErr = firebase.NewApp(ctx, nil)
+	}
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	client, clientErr := app.Auth(ctx)
+	if clientErr != nil {
+		return nil, clientErr
+	}
+	return &FirebaseProvider{Client: client}, nil
+}
+
+func (p *FirebaseProvider) VerifyToken(ctx context.Context, token string) (string, error) {
+	idToken, validateErr := p.Client.VerifyIDToken(ctx, token)
+	if validateErr != nil || idToken == nil {
+		return "", validateErr
+	}
+	return idToken.UID, nil
+}
+
+func (p *FirebaseProvider) GetIdentity(ctx context.Context, uid string) (*Identity, error) {
+	user, err := p.Client.GetUser(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{UID: user.UID, DisplayName: user.DisplayName, Email: user.Email}, nil
+}
+
+func (p *FirebaseProvider) CustomToken(ctx context.Context, uid string) (string, error) {
+	return p.Client.CustomToken(ctx, uid)
+}
@@ -0,0 +1,122 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"github.com/lithammer/shortuuid/v4"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrNotAGuest       = errors.New("only guests can be merged into an account")
+	ErrMergeIntoGuest  = errors.New("guests can't be merged into another guest")
+	ErrInvalidGuestKey = errors.New("invalid guest token")
+)
+
+// GuestProvider is the identity provider for guests, who play without signing in. Guests sign in
+// with session tokens signed by Sessions, and are created the first time they're used.
+type GuestProvider struct {
+	Sessions *LocalJWTProvider
+}
+
+func (p GuestProvider) VerifyToken(ctx context.Context, token string) (string, error) {
+	uid, err := p.Sessions.VerifyToken(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(uid, models.GuestUidPrefix) {
+		return "", errors.New("token isn't for a guest")
+	}
+	return uid, nil
+}
+
+// GetIdentity can't tell which guest uids were issued a token, so it should only be asked about
+// uids from verified tokens
+func (p GuestProvider) GetIdentity(_ context.Context, uid string) (*Identity, error) {
+	if !strings.HasPrefix(uid, models.GuestUidPrefix) {
+		return nil, models.ErrNotFound{Message: fmt.Sprintf("%s isn't a guest uid", uid), RepoMethod: "GetIdentity"}
+	}
+	suffix := strings.TrimPrefix(uid, models.GuestUidPrefix)
+	if len(suffix) > 4 {
+		suffix = suffix[:4]
+	}
+	return &Identity{UID: uid, DisplayName: "Guest " + suffix}, nil
+}
+
+func (p GuestProvider) CustomToken(_ context.Context, uid string) (string, error) {
+	return p.Sessions.issue(uid, sessionTTL)
+}
+
+// GuestHandler starts a guest session. Nothing is stored until the guest's token is first used.
+func (s *Service) GuestHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, err := s.Sessions.issue(models.GuestUidPrefix+shortuuid.New(), sessionTTL)
+		if err != nil {
+			s.Logger.Errorf("failed to start guest session: %v", err)
+			respondWithError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+		RespondWithJSON(w, http.StatusOK, sessionResponse{Token: token})
+	}
+}
+
+// MergeGuest moves everything a guest has into a signed in user's account, then deletes the guest.
+// When both played the same day, the account's board is kept.
+func (s *Service) MergeGuest(ctx context.Context, user models.User, guestToken string) (*models.User, error) {
+	if user.IsGuest() {
+		return nil, ErrMergeIntoGuest
+	}
+	guestUid, verifyErr := s.Identity.VerifyToken(ctx, guestToken)
+	if verifyErr != nil {
+		return nil, ErrInvalidGuestKey
+	}
+	if !strings.HasPrefix(guestUid, models.GuestUidPrefix) {
+		return nil, ErrNotAGuest
+	}
+
+	guest, findErr := s.findUserByUid(ctx, guestUid)
+	if _, isNotFound := findErr.(models.ErrNotFound); isNotFound {
+		// the guest never played, or was already merged
+		return &user, nil
+	} else if findErr != nil {
+		return nil, findErr
+	}
+
+	if !guest.IsGuest() {
+		// a merge of the guest into an account stopped part way, and was just finished
+		return &user, nil
+	}
+	if err := s.MergeUsers(ctx, user, *guest); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// mergeGameBoards combines two users' daily boards. The account's board is always kept for days
+// it started, boards are only taken from the other user for days the account never played. Taking
+// a better board instead would let anyone replay a day on throwaway guests until they win.
+func mergeGameBoards(accountBoards, otherBoards []*models.GameBoard) []models.GameBoard {
+	dayToBoard := make(map[int]models.GameBoard)
+	for _, board := range accountBoards {
+		dayToBoard[board.Day] = *board
+	}
+	for _, board := range otherBoards {
+		existing, ok := dayToBoard[board.Day]
+		if !ok || len(existing.Guesses) == 0 {
+			dayToBoard[board.Day] = *board
+		}
+	}
+
+	merged := make([]models.GameBoard, 0, len(dayToBoard))
+	for _, board := range dayToBoard {
+		merged = append(merged, board)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Day < merged[j].Day
+	})
+	return merged
+}
@@ -2,10 +2,14 @@ package users
 
 import (
 	"encoding/json"
-	"github.com/amanzanero/wordleboard/api/models"
+	"errors"
 	"net/http"
+	"strings"
 )
 
+// CreateUserHandler creates the user for the bearer token's uid. The token has to be verified,
+// since some identity providers can't tell which uids they've issued tokens for. oauth_id is
+// optional, and has to match the token when it's sent.
 func (s *Service) CreateUserHandler() http.HandlerFunc {
 	type requestParams struct {
 		OauthId     string `json:"oauth_id"`
@@ -22,36 +26,35 @@ func (s *Service) CreateUserHandler() http.HandlerFunc {
 			return
 		}
 
-		// check if user exists
-		_, findErr := s.Repo.FindUserByUuid(r.Context(), params.OauthId)
-		if findErr == nil {
-			s.Logger.Infof("user with id: %s already exists, exiting", params.OauthId)
-			RespondWithJSON(w, 201, map[string]string{"msg": "succeeded"})
-			return
-		} else if _, isRepoFailure := findErr.(models.ErrRepoFailed); isRepoFailure {
-			s.Logger.Errorf("failed to fetch user from repo: %v", findErr)
-			respondWithError(w, 500, findErr.Error())
+		bearerToken := strings.Replace(r.Header.Get("Authorization"), "Bearer ", "", 1)
+		uid, verifyErr := s.ValidateIDToken(r.Context(), bearerToken)
+		if verifyErr != nil || (params.OauthId != "" && params.OauthId != uid) {
+			respondWithError(w, 403, "Invalid auth")
 			return
 		}
 
-		// let's verify the oathId
-		_, fetchUserFromAuthServiceErr := s.Client.GetUser(r.Context(), params.OauthId)
-		if fetchUserFromAuthServiceErr != nil {
-			s.Logger.Infof("failed to find fetch user from firebase: %v", fetchUserFromAuthServiceErr)
-			respondWithError(w, 400, "user does not exist")
-			return
+		if params.DisplayName != "" {
+			if _, profileErr := validateDisplayName(params.DisplayName); profileErr != nil {
+				respondWithError(w, 400, "invalid display name")
+				return
+			}
 		}
 
-		// ok user doesn't exist so let's make it
-		_, createErr := s.Repo.InsertUser(r.Context(), models.NewUser{
-			ID:          params.OauthId,
-			DisplayName: params.DisplayName,
-		})
-		if createErr != nil {
-			s.Logger.Errorf("failed to create user: %v", createErr)
+		_, created, provisionErr := s.ProvisionUser(r.Context(), uid, params.DisplayName)
+		if errors.Is(provisionErr, ErrUnknownIdentity) {
+			s.Logger.Infof("failed to fetch user from identity provider: %v", provisionErr)
+			respondWithError(w, 400, "user does not exist")
+			return
+		} else if provisionErr != nil {
+			s.Logger.Errorf("failed to create user: %v", provisionErr)
 			respondWithError(w, 500, "internal error")
 			return
 		}
+		if !created {
+			s.Logger.Infof("user with id: %s already exists, exiting", uid)
+			RespondWithJSON(w, 201, map[string]string{"msg": "succeeded"})
+			return
+		}
 		RespondWithJSON(w, 200, map[string]string{"msg": "success"})
 	}
 }
@@ -0,0 +1,19 @@
+package users
+
+import "context"
+
+// Identity is who an identity provider says a user is
+type Identity struct {
+	UID         string
+	DisplayName string
+	Email       string
+}
+
+// IdentityProvider is where users sign in. It verifies the bearer tokens sent with requests, looks
+// up who a uid belongs to, and mints tokens for a uid.
+type IdentityProvider interface {
+	// VerifyToken checks a bearer token and returns the uid it was issued to
+	VerifyToken(ctx context.Context, token string) (string, error)
+	GetIdentity(ctx context.Context, uid string) (*Identity, error)
+	CustomToken(ctx context.Context, uid string) (string, error)
+}
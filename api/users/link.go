@@ -0,0 +1,151 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"strings"
+)
+
+var (
+	ErrInvalidLinkToken = errors.New("invalid token for the login being linked")
+	ErrCannotLinkGuest  = errors.New("guests are merged, not linked")
+	ErrMergeIntoSelf    = errors.New("a user can't be merged into themselves")
+	ErrAlreadyMerging   = errors.New("the user is already being merged into another user")
+)
+
+// LinkIdentity links another login to a user, so signing in with either finds the same user. The
+// token proves the user owns the other login. If that login already has its own user, the two
+// users are merged.
+func (s *Service) LinkIdentity(ctx context.Context, user models.User, token string) (*models.User, error) {
+	if user.IsGuest() {
+		// guests sign in to an account and merge into it instead
+		return nil, ErrMergeIntoGuest
+	}
+	uid, verifyErr := s.Identity.VerifyToken(ctx, token)
+	if verifyErr != nil {
+		return nil, ErrInvalidLinkToken
+	}
+	if strings.HasPrefix(uid, models.GuestUidPrefix) {
+		return nil, ErrCannotLinkGuest
+	}
+	if uid == user.OauthId {
+		return &user, nil
+	}
+
+	other, findErr := s.findUserByUid(ctx, uid)
+	if _, isNotFound := findErr.(models.ErrNotFound); isNotFound {
+		err := s.Repo.AddIdentities(ctx, user.ID, []string{uid})
+		if err == nil {
+			return &user, nil
+		} else if _, exists := err.(models.ErrAlreadyExists); !exists {
+			return nil, err
+		}
+		// the uid signed in for the first time since it was looked up, so its new user is merged
+		other, findErr = s.findUserByUid(ctx, uid)
+	}
+	if findErr != nil {
+		return nil, findErr
+	}
+	if other.ID == user.ID {
+		return &user, nil
+	}
+
+	if err := s.MergeUsers(ctx, user, *other); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// findUserByUid finds the user for a uid. If they were being merged into another user when the
+// merge stopped part way, the merge is finished and that user is returned instead.
+func (s *Service) findUserByUid(ctx context.Context, uid string) (*models.User, error) {
+	user, err := s.Repo.FindUserByUuid(ctx, uid)
+	if err != nil || user.MergedInto == nil {
+		return user, err
+	}
+	into, intoErr := s.Repo.FindUserById(ctx, *user.MergedInto)
+	if intoErr != nil {
+		return nil, intoErr
+	}
+	if err := s.MergeUsers(ctx, *into, *user); err != nil {
+		return nil, err
+	}
+	return into, nil
+}
+
+// MergeUsers moves everything one user has into another user, then deletes them. When both played
+// the same game, into's board is kept. The user is marked as merging first and their uids move last,
+// so signing in with them finds the user being merged until everything else has moved. Every step
+// can be repeated, a merge that stops part way is finished the next time one of its uids signs in,
+// or by ResumeMerges.
+func (s *Service) MergeUsers(ctx context.Context, into, from models.User) error {
+	if into.ID == from.ID {
+		return ErrMergeIntoSelf
+	}
+	if from.MergedInto != nil && *from.MergedInto != into.ID {
+		return ErrAlreadyMerging
+	}
+	defer s.invalidateCachedUser(into.ID)
+	defer s.invalidateCachedUser(from.ID)
+
+	if err := s.Repo.MarkUserMerged(ctx, from.ID, into.ID); err != nil {
+		return err
+	}
+	intoBoards, intoErr := s.Repo.FindGameBoardsForUser(ctx, into.ID)
+	if intoErr != nil {
+		return intoErr
+	}
+	fromBoards, fromErr := s.Repo.FindGameBoardsForUser(ctx, from.ID)
+	if fromErr != nil {
+		return fromErr
+	}
+	if err := s.Repo.ReplaceGameBoards(ctx, into.ID, mergeGameBoards(intoBoards, fromBoards)); err != nil {
+		return err
+	}
+	if err := s.Repo.TransferMultiBoards(ctx, from.ID, into.ID); err != nil {
+		return err
+	}
+	if err := s.Repo.TransferAchievements(ctx, from.ID, into.ID); err != nil {
+		return err
+	}
+	if err := s.Repo.TransferLeaderboards(ctx, from.ID, into.ID); err != nil {
+		return err
+	}
+	if err := s.Repo.TransferUserGames(ctx, from.ID, into.ID); err != nil {
+		return err
+	}
+	if err := s.AccessTokens.TransferAccessTokens(ctx, from.ID, into.ID); err != nil {
+		return err
+	}
+	if s.Blobs != nil {
+		// into keeps their own avatar
+		if err := s.Blobs.DeletePrefix(ctx, avatarPrefix(from.ID)); err != nil {
+			return err
+		}
+	}
+	if err := s.Repo.TransferIdentities(ctx, from.ID, into.ID); err != nil {
+		return err
+	}
+	return s.Repo.DeleteUser(ctx, from.ID)
+}
+
+// ResumeMerges finishes every merge that stopped part way
+func (s *Service) ResumeMerges(ctx context.Context) error {
+	merged, err := s.Repo.FindMergedUsers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, from := range merged {
+		into, findErr := s.Repo.FindUserById(ctx, *from.MergedInto)
+		if findErr != nil {
+			return fmt.Errorf("failed to find user %s that %s is merged into: %w", *from.MergedInto, from.ID, findErr)
+		}
+		if mergeErr := s.MergeUsers(ctx, *into, *from); mergeErr != nil {
+			return fmt.Errorf("failed to finish merging user %s into %s: %w", from.ID, into.ID, mergeErr)
+		}
+		s.Logger.Infof("finished merging user %s into %s", from.ID, into.ID)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package users
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	localIssuer   = "wordleboard"
+	localTokenTTL = time.Hour
+)
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+type jwtClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// LocalJWTProvider signs users in with JWTs it signs itself, so dev, tests, and self-hosted
+// servers don't need Firebase. Tokens are signed with HS256 when the key is a shared secret, or
+// RS256 when it's a PEM encoded RSA private key. It doesn't keep a directory of users, so any uid it
+// has signed a token for is a valid identity.
+type LocalJWTProvider struct {
+	alg        string
+	secret     []byte
+	privateKey *rsa.PrivateKey
+	now        func() time.Time
+}
+
+func NewLocalJWTProvider(key []byte) (*LocalJWTProvider, error) {
+	if len(key) == 0 {
+		return nil, errors.New("local jwt provider needs a signing key")
+	}
+
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return &LocalJWTProvider{alg: "HS256", secret: key, now: time.Now}, nil
+	}
+
+	privateKey, parseErr := parseRSAPrivateKey(block.Bytes)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	return &LocalJWTProvider{alg: "RS256", privateKey: privateKey, now: time.Now}, nil
+}
+
+func parseRSAPrivateKey(der []byte) (*rsa.PrivateKey, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse rsa private key: %v", err)
+	}
+	rsaKey, isRSA := key.(*rsa.PrivateKey)
+	if !isRSA {
+		return nil, errors.New("local jwt provider only supports rsa private keys")
+	}
+	return rsaKey, nil
+}
+
+func (p *LocalJWTProvider) VerifyToken(_ context.Context, token string) (string, error) {
+	claims, err := p.verifyClaims(token)
+	if err != nil {
+		return "", err
+	}
+	return claims.Subject, nil
+}
+
+// verifyClaims checks a token's signature and expiry, and returns its claims
+func (p *LocalJWTProvider) verifyClaims(token string) (*jwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	// only accept the algorithm we sign with, so a token can't pick a weaker one
+	if header.Alg != p.alg {
+		return nil, fmt.Errorf("unexpected signing algorithm %s", header.Alg)
+	}
+	signature, decodeErr := base64.RawURLEncoding.DecodeString(parts[2])
+	if decodeErr != nil {
+		return nil, errors.New("malformed token signature")
+	}
+	if err := p.verify(parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if claims.Issuer != localIssuer {
+		return nil, fmt.Errorf("unexpected issuer %s", claims.Issuer)
+	}
+	if p.now().Unix() >= claims.ExpiresAt {
+		return nil, errors.New("token has expired")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+	return &claims, nil
+}
+
+// GetIdentity can't tell which uids were issued a token, so it should only be asked about uids from
+// verified tokens
+func (p *LocalJWTProvider) GetIdentity(_ context.Context, uid string) (*Identity, error) {
+	return &Identity{UID: uid}, nil
+}
+
+func (p *LocalJWTProvider) CustomToken(_ context.Context, uid string) (string, error) {
+	return p.issue(uid, localTokenTTL)
+}
+
+// issue signs a token for a uid that's valid for ttl
+func (p *LocalJWTProvider) issue(uid string, ttl time.Duration) (string, error) {
+	now := p.now()
+	return p.sign(jwtClaims{
+		Issuer:    localIssuer,
+		Subject:   uid,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	})
+}
+
+func (p *LocalJWTProvider) sign(claims jwtClaims) (string, error) {
+	header, _ := json.Marshal(jwtHeader{Alg: p.alg, Typ: "JWT"})
+	payload, claimsErr := json.Marshal(claims)
+	if claimsErr != nil {
+		return "", claimsErr
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	var signature []byte
+	if p.alg == "RS256" {
+		var signErr error
+		signature, signErr = rsa.SignPKCS1v15(rand.Reader, p.privateKey, crypto.SHA256, digest[:])
+		if signErr != nil {
+			return "", signErr
+		}
+	} else {
+		mac := hmac.New(sha256.New, p.secret)
+		mac.Write([]byte(signingInput))
+		signature = mac.Sum(nil)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func (p *LocalJWTProvider) verify(signingInput string, signature []byte) error {
+	if p.alg == "RS256" {
+		digest := sha256.Sum256([]byte(signingInput))
+		if err := rsa.VerifyPKCS1v15(&p.privateKey.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+			return errors.New("invalid token signature")
+		}
+		return nil
+	}
+
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(signingInput))
+	if !hmac.Equal(mac.Sum(nil), signature) {
+		return errors.New("invalid token signature")
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errors.New("malformed token segment")
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return errors.New("malformed token segment")
+	}
+	return nil
+}
@@ -2,10 +2,12 @@ package users
 
 import (
 	"context"
+	"errors"
 	"github.com/amanzanero/wordleboard/api/logging"
 	"github.com/amanzanero/wordleboard/api/models"
 	"net/http"
 	"strings"
+	"time"
 )
 
 // A private key for context that only this package can access. This is important
@@ -17,38 +19,98 @@ type contextKey struct {
 }
 
 func (s *Service) AuthMiddleware(handler http.Handler) http.HandlerFunc {
+	serveAs := func(w http.ResponseWriter, r *http.Request, user *models.User, accessToken *models.AccessToken) {
+		if user.BannedAt != nil {
+			http.Error(w, "Banned", http.StatusForbidden)
+			return
+		}
+		ctx := context.WithValue(r.Context(), userCtxKey, user)
+		if accessToken != nil {
+			ctx = context.WithValue(ctx, accessTokenCtxKey, accessToken)
+		}
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		auth := r.Header.Get("Authorization")
 		bearerToken := strings.Replace(auth, "Bearer ", "", 1)
+		if s.AuthCache != nil {
+			if user, accessToken, found := s.AuthCache.Get(bearerToken); found {
+				serveAs(w, r, user, accessToken)
+				return
+			}
+		}
+
+		if strings.HasPrefix(bearerToken, AccessTokenPrefix) {
+			user, accessToken, err := s.authenticateAccessToken(r.Context(), bearerToken)
+			if errors.Is(err, ErrInvalidAccessToken) {
+				http.Error(w, "Invalid auth", http.StatusForbidden)
+				return
+			} else if err != nil {
+				logging.FromContext(r.Context()).Errorf("could not authenticate access token: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			if s.AuthCache != nil {
+				// access tokens don't expire, they're revoked
+				s.AuthCache.Put(bearerToken, *user, accessToken, time.Time{})
+			}
+			serveAs(w, r, user, accessToken)
+			return
+		}
+
 		userUuid, err := s.ValidateIDToken(r.Context(), bearerToken)
 		if err != nil {
 			http.Error(w, "Invalid auth", http.StatusForbidden)
 			return
 		}
-		user, lookupErr := s.Repo.FindUserByUuid(r.Context(), userUuid)
-		if lookupErr != nil {
-			logging.FromContext(r.Context()).Infof("could not find authenticated user with uuid: %s, creating a new user", userUuid)
+		user, created, provisionErr := s.ProvisionUser(r.Context(), userUuid, "")
+		if errors.Is(provisionErr, ErrUnknownIdentity) {
+			logging.FromContext(r.Context()).Errorf("could not create user: %v", provisionErr)
+			http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+			return
+		} else if provisionErr != nil {
+			logging.FromContext(r.Context()).Errorf("could not find or create user: %v", provisionErr)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if created {
+			logging.FromContext(r.Context()).Infof("created a new user for uuid: %s", userUuid)
+		}
 
-			// try and create user
-			firebaseUser, firebaseErr := s.Client.GetUser(r.Context(), userUuid)
-			if firebaseErr != nil {
-				http.Error(w, "service unavailable", http.StatusServiceUnavailable)
-				return
+		if s.AuthCache != nil {
+			// a token that isn't a readable JWT isn't cached, since when it expires isn't known
+			if expiresAt, hasExpiry := jwtExpiry(bearerToken); hasExpiry {
+				s.AuthCache.Put(bearerToken, *user, nil, expiresAt)
 			}
+		}
+		serveAs(w, r, user, nil)
+	}
+}
 
-			user, err = s.Repo.InsertUser(r.Context(), models.NewUser{
-				ID:          firebaseUser.UID,
-				DisplayName: firebaseUser.DisplayName,
-			})
-			if err != nil {
-				logging.FromContext(r.Context()).Errorf("could not create user: %v", err)
-				http.Error(w, "internal error", http.StatusInternalServerError)
-				return
-			}
+// RequireScope keeps requests made with an access token without the scope out of a handler.
+// REQUIRES Middleware to have run.
+func RequireScope(scope models.TokenScope, handler http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accessToken := AccessTokenForContext(r.Context())
+		if accessToken != nil && !accessToken.HasScope(scope) {
+			http.Error(w, "access token is missing the "+scope.String()+" scope", http.StatusForbidden)
+			return
 		}
+		handler.ServeHTTP(w, r)
+	}
+}
 
-		ctx := context.WithValue(r.Context(), userCtxKey, user)
-		r = r.WithContext(ctx)
+// RequireRole keeps users without the role out of a handler. Like the @hasRole directive, requests
+// made with an access token never have a role. REQUIRES Middleware to have run.
+func RequireRole(role models.Role, handler http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := ForContext(r.Context())
+		if user == nil || AccessTokenForContext(r.Context()) != nil || !user.HasRole(role) {
+			http.Error(w, "the "+role.String()+" role is required", http.StatusForbidden)
+			return
+		}
 		handler.ServeHTTP(w, r)
 	}
 }
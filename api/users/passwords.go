@@ -0,0 +1,68 @@
+package users
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"strings"
+)
+
+// argon2id parameters, following the second recommended option in RFC 9106
+const (
+	argonTime    = 3
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+	argonKeyLen  = 32
+	argonSaltLen = 16
+)
+
+// hashPassword hashes a password with argon2id, in the PHC string format so the parameters can be
+// changed later without breaking existing hashes
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, argonSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		argonMemory,
+		argonTime,
+		argonThreads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// verifyPassword checks a password against a hash made by hashPassword
+func verifyPassword(password, encoded string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false, errors.New("unsupported password hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, errors.New("unsupported argon2 version")
+	}
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false, errors.New("malformed argon2 parameters")
+	}
+	salt, saltErr := base64.RawStdEncoding.DecodeString(parts[4])
+	if saltErr != nil {
+		return false, errors.New("malformed argon2 salt")
+	}
+	expected, keyErr := base64.RawStdEncoding.DecodeString(parts[5])
+	if keyErr != nil {
+		return false, errors.New("malformed argon2 key")
+	}
+
+	key := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(expected)))
+	return subtle.ConstantTimeCompare(key, expected) == 1, nil
+}
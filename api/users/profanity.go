@@ -0,0 +1,97 @@
+package users
+
+import (
+	"strings"
+	"unicode"
+)
+
+// blockedWords are never allowed as a word in a display name, on their own or with one of
+// blockedSuffixes. The list is short on purpose, it only has to catch the obvious cases, and leaves
+// out words that are also names.
+var blockedWords = []string{
+	"asshole",
+	"bastard",
+	"bitch",
+	"cunt",
+	"dickhead",
+	"fag",
+	"faggot",
+	"fuck",
+	"nigga",
+	"nigger",
+	"penis",
+	"pussy",
+	"retard",
+	"shit",
+	"slut",
+	"whore",
+}
+
+// blockedSuffixes are the endings that still make a blocked word. Matching whole words with only
+// these endings lets names like Dickens or Scunthorpe through.
+var blockedSuffixes = []string{"", "s", "es", "ed", "er", "ers", "ing", "head", "heads"}
+
+// lookalikes undo the common ways of dodging a filter with numbers and symbols
+var lookalikes = map[rune]rune{
+	'0': 'o',
+	'1': 'i',
+	'!': 'i',
+	'3': 'e',
+	'4': 'a',
+	'@': 'a',
+	'5': 's',
+	'$': 's',
+	'7': 't',
+	'+': 't',
+}
+
+// isProfane checks each word of a display name against blockedWords, ignoring case and lookalike
+// characters. Runs of single letters are read as one word, so spelling a word out doesn't get past
+// it.
+func isProfane(name string) bool {
+	for _, word := range displayNameWords(name) {
+		for _, blocked := range blockedWords {
+			if !strings.HasPrefix(word, blocked) {
+				continue
+			}
+			for _, suffix := range blockedSuffixes {
+				if word == blocked+suffix {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// displayNameWords splits a lowercased display name into words of letters, after undoing
+// lookalikes and joining runs of single letters
+func displayNameWords(name string) []string {
+	mapped := strings.Map(func(r rune) rune {
+		if replacement, ok := lookalikes[r]; ok {
+			return replacement
+		}
+		return r
+	}, strings.ToLower(name))
+	split := strings.FieldsFunc(mapped, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	words := make([]string, 0, len(split))
+	spelled := ""
+	for _, word := range split {
+		if len([]rune(word)) == 1 {
+			spelled += word
+			continue
+		}
+		if spelled != "" {
+			words = append(words, spelled)
+			spelled = ""
+		}
+		words = append(words, word)
+	}
+	if spelled != "" {
+		words = append(words, spelled)
+	}
+	return words
+}
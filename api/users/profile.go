@@ -0,0 +1,169 @@
+package users
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"github.com/lithammer/shortuuid/v4"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const maxAvatarBytes = 1 << 20
+
+var ErrAvatarsDisabled = errors.New("avatar uploads aren't enabled")
+
+// avatarExtensions are the image types avatars can be, by their sniffed content type
+var avatarExtensions = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpg",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// UpdateProfile changes a user's display name and preferences. Preferences that aren't set in the
+// input are left as they are.
+func (s *Service) UpdateProfile(ctx context.Context, user models.User, input models.ProfileInput) (models.ProfileResult, error) {
+	var update models.ProfileUpdate
+	if input.DisplayName != nil {
+		displayName, profileErr := validateDisplayName(*input.DisplayName)
+		if profileErr != nil {
+			return *profileErr, nil
+		}
+		update.DisplayName = &displayName
+	}
+	if input.Preferences != nil {
+		preferences, profileErr := mergePreferences(user.Preferences, *input.Preferences)
+		if profileErr != nil {
+			return *profileErr, nil
+		}
+		update.Preferences = &preferences
+	}
+
+	updated, err := s.Repo.UpdateProfile(ctx, user.ID, update)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateCachedUser(user.ID)
+	return *updated, nil
+}
+
+// UploadAvatar stores a new avatar for a user, and deletes their old one. Avatars have to be small
+// png, jpeg, gif, or webp images.
+func (s *Service) UploadAvatar(ctx context.Context, user models.User, contents io.Reader) (models.ProfileResult, error) {
+	if s.Blobs == nil {
+		return nil, ErrAvatarsDisabled
+	}
+
+	// read one byte past the limit to tell if the upload is too big
+	image, readErr := io.ReadAll(io.LimitReader(contents, maxAvatarBytes+1))
+	if readErr != nil {
+		return nil, readErr
+	}
+	if len(image) == 0 || len(image) > maxAvatarBytes {
+		return models.ProfileResultError{Error: models.ProfileErrorInvalidAvatar}, nil
+	}
+	contentType := http.DetectContentType(image)
+	extension, isImage := avatarExtensions[contentType]
+	if !isImage {
+		return models.ProfileResultError{Error: models.ProfileErrorInvalidAvatar}, nil
+	}
+
+	// every upload gets a new key, so clients never show a cached old avatar
+	key := fmt.Sprintf("%s%s.%s", avatarPrefix(user.ID), shortuuid.New(), extension)
+	url, putErr := s.Blobs.Put(ctx, key, contentType, bytes.NewReader(image))
+	if putErr != nil {
+		return nil, putErr
+	}
+
+	updated, err := s.Repo.UpdateProfile(ctx, user.ID, models.ProfileUpdate{AvatarUrl: &url})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateCachedUser(user.ID)
+
+	// the new avatar is already saved, an old one that can't be deleted is only left behind
+	if oldKey, hasOld := avatarKey(user); hasOld {
+		if deleteErr := s.Blobs.Delete(ctx, oldKey); deleteErr != nil {
+			s.Logger.Errorf("failed to delete old avatar %s: %v", oldKey, deleteErr)
+		}
+	}
+	return *updated, nil
+}
+
+func avatarPrefix(userId string) string {
+	return fmt.Sprintf("avatars/%s/", userId)
+}
+
+// avatarKey finds the blob key of a user's avatar from its URL. Every avatar is stored under the
+// user's prefix, with a name that's only used once.
+func avatarKey(user models.User) (string, bool) {
+	if user.AvatarUrl == nil {
+		return "", false
+	}
+	prefix := avatarPrefix(user.ID)
+	index := strings.LastIndex(*user.AvatarUrl, "/"+prefix)
+	if index == -1 {
+		return "", false
+	}
+	name := (*user.AvatarUrl)[index+1+len(prefix):]
+	if name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+	return prefix + name, true
+}
+
+// defaultDisplayName is given to new users when none of their names can be used
+const defaultDisplayName = "Player"
+
+// provisionedDisplayName picks the first of a new user's names that passes validateDisplayName.
+// Names from identity providers can be long, so they're cut short before they're checked.
+func provisionedDisplayName(names ...string) string {
+	for _, name := range names {
+		if valid, profileErr := validateDisplayName(truncateDisplayName(strings.TrimSpace(name))); profileErr == nil {
+			return valid
+		}
+	}
+	return defaultDisplayName
+}
+
+func validateDisplayName(name string) (string, *models.ProfileResultError) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxDisplayName {
+		return "", &models.ProfileResultError{Error: models.ProfileErrorInvalidDisplayName}
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return "", &models.ProfileResultError{Error: models.ProfileErrorInvalidDisplayName}
+		}
+	}
+	if isProfane(name) {
+		return "", &models.ProfileResultError{Error: models.ProfileErrorInappropriateDisplayName}
+	}
+	return name, nil
+}
+
+func mergePreferences(current models.UserPreferences, input models.PreferencesInput) (models.UserPreferences, *models.ProfileResultError) {
+	if input.TimeZone != nil {
+		// only IANA names are allowed, and Local would mean the server's time zone
+		if *input.TimeZone == "" || *input.TimeZone == "Local" {
+			return current, &models.ProfileResultError{Error: models.ProfileErrorInvalidTimeZone}
+		}
+		if _, err := time.LoadLocation(*input.TimeZone); err != nil {
+			return current, &models.ProfileResultError{Error: models.ProfileErrorInvalidTimeZone}
+		}
+		current.TimeZone = *input.TimeZone
+	}
+	if input.HardModeDefault != nil {
+		current.HardModeDefault = *input.HardModeDefault
+	}
+	if input.Palette != nil {
+		current.Palette = *input.Palette
+	}
+	return current, nil
+}
@@ -0,0 +1,69 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+)
+
+var ErrUnknownIdentity = errors.New("the identity provider doesn't know this uid")
+
+// ProvisionUser finds the user for a uid, and creates them the first time the uid is seen. The uid
+// has to come from a verified token, since the local and guest providers accept any uid they're
+// asked about. The display name falls back to the one from the identity provider when it's empty or
+// can't be used. It's safe to call concurrently for the same uid, only one user is ever created. The
+// bool reports whether the user was created.
+func (s *Service) ProvisionUser(ctx context.Context, uid, displayName string) (*models.User, bool, error) {
+	user, findErr := s.findUserByUid(ctx, uid)
+	if findErr == nil && user.DeletedAt == nil {
+		return user, false, nil
+	}
+	if findErr == nil {
+		// the user's deletion stopped part way, it's finished before they start over as a new user
+		if err := s.DeleteAccount(ctx, *user); err != nil {
+			return nil, false, err
+		}
+	} else if _, isNotFound := findErr.(models.ErrNotFound); !isNotFound {
+		return nil, false, findErr
+	}
+
+	identity, identityErr := s.Identity.GetIdentity(ctx, uid)
+	if identityErr != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrUnknownIdentity, identityErr)
+	}
+	displayName = provisionedDisplayName(displayName, identity.DisplayName)
+	return s.Repo.UpsertUser(ctx, models.NewUser{ID: identity.UID, DisplayName: displayName})
+}
+
+// MergeDuplicateUsers merges users that share a uid into the oldest of them. They could be created
+// before provisioning was safe to run concurrently, or while a uid was being linked. A user in more
+// than one group is only merged once, and later groups use the user they were merged into.
+func (s *Service) MergeDuplicateUsers(ctx context.Context) error {
+	duplicates, err := s.Repo.FindDuplicateUsers(ctx)
+	if err != nil {
+		return err
+	}
+	mergedInto := make(map[string]*models.User)
+	current := func(user *models.User) *models.User {
+		for mergedInto[user.ID] != nil {
+			user = mergedInto[user.ID]
+		}
+		return user
+	}
+	for _, group := range duplicates {
+		into := current(group[0])
+		for _, from := range group[1:] {
+			from = current(from)
+			if from.ID == into.ID {
+				continue
+			}
+			if err := s.MergeUsers(ctx, *into, *from); err != nil {
+				return fmt.Errorf("failed to merge duplicate user %s into %s: %w", from.ID, into.ID, err)
+			}
+			mergedInto[from.ID] = into
+			s.Logger.Infof("merged duplicate user %s into %s", from.ID, into.ID)
+		}
+	}
+	return nil
+}
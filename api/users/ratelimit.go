@@ -0,0 +1,90 @@
+package users
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiter allows each key at most a number of requests per window. Windows are fixed, so up to
+// twice the limit can get through around the end of a window. Each server has its own limiter.
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	windows   map[string]*rateWindow
+	lastSweep time.Time
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:   limit,
+		window:  window,
+		now:     time.Now,
+		windows: make(map[string]*rateWindow),
+	}
+}
+
+// Allow counts a request for a key, and reports whether it's within the limit
+func (l *RateLimiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= l.window {
+		// windows that ended are dropped once per window, so keys that stop coming back are forgotten
+		for k, w := range l.windows {
+			if now.Sub(w.start) >= l.window {
+				delete(l.windows, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &rateWindow{start: now}
+		l.windows[key] = w
+	}
+	w.count++
+	return w.count <= l.limit
+}
+
+// clientIP is the address a request came from. App Engine sets X-Appengine-User-Ip and drops it
+// from incoming requests, so unlike X-Forwarded-For it can't be set by the client.
+func clientIP(r *http.Request) string {
+	if ip := r.Header.Get("X-Appengine-User-Ip"); ip != "" {
+		return ip
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// allowAccountRequest rate limits the account endpoints by client IP, and by email when the
+// request has one. Responds with 429 and returns false when either limit is hit.
+func (s *Service) allowAccountRequest(w http.ResponseWriter, r *http.Request, email string) bool {
+	allowed := true
+	if s.AccountIPLimiter != nil && !s.AccountIPLimiter.Allow(clientIP(r)) {
+		allowed = false
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email != "" && s.AccountEmailLimiter != nil && !s.AccountEmailLimiter.Allow(email) {
+		allowed = false
+	}
+	if !allowed {
+		respondWithError(w, http.StatusTooManyRequests, "too many requests, try again later")
+	}
+	return allowed
+}
@@ -2,34 +2,35 @@ package users
 
 import (
 	"context"
-	"firebase.google.com/go/v4"
-	"firebase.google.com/go/v4/auth"
 	"github.com/amanzanero/wordleboard/api/models"
-	"github.com/amanzanero/wordleboard/api/secrets"
 	"github.com/sirupsen/logrus"
-	"google.golang.org/api/option"
 )
 
 type Service struct {
-	Repo   models.UserRepo
-	Client *auth.Client
-	Logger *logrus.Logger
+	Repo         models.UserRepo
+	AccessTokens models.AccessTokenRepo
+	Identity     IdentityProvider
+	Logger       *logrus.Logger
+	AuthCache    *AuthCache // tokens are verified on every request when this isn't set
+
+	// first-party accounts are only available when these are set
+	Accounts  models.AccountRepo
+	Sessions  *LocalJWTProvider
+	Email     EmailSender
+	PublicUrl string
+
+	// the account endpoints aren't rate limited when these aren't set
+	AccountIPLimiter    *RateLimiter
+	AccountEmailLimiter *RateLimiter
+
+	// avatars can only be uploaded when this is set
+	Blobs BlobStore
 }
 
-func NewAuthClient(ctx context.Context, secretManager secrets.Manager) (*auth.Client, error) {
-	if secretManager.IsDev {
-		opt := option.WithCredentialsJSON(secretManager.GetSecretBytes(secrets.FirebaseCredentials))
-		app, appErr := firebase.NewApp(ctx, nil, opt)
-		if appErr != nil {
-			return nil, appErr
-		}
-		return app.Auth(ctx)
-	} else {
-		app, appErr := firebase.NewApp(ctx, nil)
-		if appErr != nil {
-			return nil, appErr
-		}
-		return app.Auth(ctx)
+// invalidateCachedUser makes the next request from a user that changed look them up again
+func (s *Service) invalidateCachedUser(userId string) {
+	if s.AuthCache != nil {
+		s.AuthCache.InvalidateUser(userId)
 	}
 }
 
@@ -0,0 +1,89 @@
+package wordle
+
+import (
+	"context"
+	"github.com/amanzanero/wordleboard/api/models"
+	"github.com/lithammer/shortuuid/v4"
+	"time"
+)
+
+// adversarialFeedback picks the feedback for a guess that keeps the most candidates alive. Ties go to
+// the least revealing pattern, then the lowest pattern, so every player sees the same game.
+func adversarialFeedback(candidates []string, guess string) (pattern, []string) {
+	var best pattern
+	var bestBucket []string
+	for p, bucket := range partition(candidates, guess) {
+		if bestBucket == nil ||
+			len(bucket) > len(bestBucket) ||
+			len(bucket) == len(bestBucket) && p.weight() < best.weight() ||
+			len(bucket) == len(bestBucket) && p.weight() == best.weight() && p < best {
+			best, bestBucket = p, bucket
+		}
+	}
+	return best, bestBucket
+}
+
+// absurdleCandidates replays a board's guesses to find the solutions that are still possible
+func absurdleCandidates(board models.AbsurdleBoard) []string {
+	candidates := solutions
+	for _, row := range board.Guesses {
+		candidates = filterCandidates(candidates, rowWord(row), rowToPattern(row))
+	}
+	return candidates
+}
+
+func (s *Service) StartAbsurdle(ctx context.Context, userId string) (*models.AbsurdleBoard, error) {
+	loadWords()
+
+	board := models.AbsurdleBoard{
+		ID:        shortuuid.New(),
+		UserId:    userId,
+		Guesses:   make([][]models.GuessState, 0),
+		State:     models.GameStateInProgress,
+		Remaining: len(solutions),
+		CreatedAt: time.Now().UTC(),
+	}
+	insertErr := s.repo.InsertAbsurdleBoard(ctx, board)
+	if insertErr != nil {
+		return nil, insertErr
+	}
+	return &board, nil
+}
+
+func (s *Service) GetAbsurdleBoard(ctx context.Context, userId, id string) (*models.AbsurdleBoard, error) {
+	return s.repo.FindAbsurdleBoard(ctx, userId, id)
+}
+
+// AbsurdleGuess plays a guess on an adversarial board. The server never commits to a solution, and
+// the game is only won once a single candidate remains and it gets guessed.
+func (s *Service) AbsurdleGuess(ctx context.Context, userId, id, guess string) (models.GuessResult, error) {
+	loadWords()
+
+	board, lookupErr := s.repo.FindAbsurdleBoard(ctx, userId, id)
+	if lookupErr != nil {
+		return nil, lookupErr
+	}
+
+	if board.State != models.GameStateInProgress {
+		return board, nil
+	}
+
+	if invalid := validateGuess(guess); invalid != nil {
+		return *invalid, nil
+	}
+
+	p, remaining := adversarialFeedback(absurdleCandidates(*board), guess)
+	board.Guesses = append(board.Guesses, p.row(guess))
+	board.Remaining = len(remaining)
+	if p == solvedPattern {
+		board.State = models.GameStateWon
+		completedAt := time.Now().UTC()
+		board.CompletedAt = &completedAt
+	}
+
+	updateErr := s.repo.UpdateAbsurdleBoard(ctx, *board)
+	if updateErr != nil {
+		return nil, updateErr
+	}
+	return board, nil
+}
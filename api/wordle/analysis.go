@@ -0,0 +1,196 @@
+package wordle
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maxCachedBestGuesses bounds how many searches for the best guess are remembered
+const maxCachedBestGuesses = 10000
+
+var (
+	// guessList is every allowed guess in alphabetical order, so searches for the best guess are
+	// deterministic
+	guessList     []string
+	guessListOnce sync.Once
+
+	// openerBest is the best guess against every solution, it's the same for every board so it's
+	// only searched for once
+	openerBest     string
+	openerBestBits float64
+	openerBestOnce sync.Once
+
+	bestGuesses = newBestGuessCache(maxCachedBestGuesses)
+)
+
+// bestGuessCache remembers the best guess against a set of candidates, so boards that narrowed the
+// solution down the same way don't search every allowed guess again. It holds at most maxEntries
+// searches, dropping the least recently used.
+type bestGuessCache struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]*list.Element
+	recency *list.List
+}
+
+type bestGuessEntry struct {
+	key  [sha256.Size]byte
+	best string
+	bits float64
+}
+
+func newBestGuessCache(maxEntries int) *bestGuessCache {
+	return &bestGuessCache{
+		maxEntries: maxEntries,
+		entries:    make(map[[sha256.Size]byte]*list.Element),
+		recency:    list.New(),
+	}
+}
+
+// get finds the best guess against the candidates, searching for it if it isn't cached
+func (c *bestGuessCache) get(candidates []string) (string, float64) {
+	// candidates are always in solution list order, so the same set always has the same key
+	key := sha256.Sum256([]byte(strings.Join(candidates, ",")))
+	c.mu.Lock()
+	if element, found := c.entries[key]; found {
+		c.recency.MoveToFront(element)
+		entry := element.Value.(*bestGuessEntry)
+		c.mu.Unlock()
+		return entry.best, entry.bits
+	}
+	c.mu.Unlock()
+
+	// the search is slow, so it runs without the lock. Boards analyzed at the same time can both
+	// search for the same guess, they come up with the same answer.
+	best, bits := bestGuess(candidates)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, found := c.entries[key]; !found {
+		c.entries[key] = c.recency.PushFront(&bestGuessEntry{key: key, best: best, bits: bits})
+		for c.recency.Len() > c.maxEntries {
+			oldest := c.recency.Remove(c.recency.Back()).(*bestGuessEntry)
+			delete(c.entries, oldest.key)
+		}
+	}
+	return best, bits
+}
+
+// expectedBits is the entropy of the feedback a guess would get, the information it's expected to
+// gain against the candidates
+func expectedBits(candidates []string, guess string) float64 {
+	var counts [int(solvedPattern) + 1]int
+	for _, candidate := range candidates {
+		counts[feedback(candidate, guess)] += 1
+	}
+
+	total := float64(len(candidates))
+	bits := 0.0
+	for _, count := range counts {
+		if count == 0 {
+			continue
+		}
+		p := float64(count) / total
+		bits -= p * math.Log2(p)
+	}
+	return bits
+}
+
+// bestGuess searches every allowed guess for the one with the most expected information. Ties go
+// to a word that could still be the solution, then alphabetically.
+func bestGuess(candidates []string) (string, float64) {
+	if len(candidates) == 0 {
+		// the guesses ruled out every solution, so there's nothing to guess
+		return "", 0
+	}
+	if len(candidates) <= 2 {
+		// guessing a candidate can't do worse than any other word
+		return candidates[0], expectedBits(candidates, candidates[0])
+	}
+
+	guessListOnce.Do(func() {
+		guessList = make([]string, 0, len(guesses))
+		for word := range guesses {
+			guessList = append(guessList, word)
+		}
+		sort.Strings(guessList)
+	})
+
+	isCandidate := make(map[string]bool, len(candidates))
+	for _, candidate := range candidates {
+		isCandidate[candidate] = true
+	}
+
+	best, bestBits := "", -1.0
+	for _, word := range guessList {
+		bits := expectedBits(candidates, word)
+		if bits > bestBits || bits == bestBits && isCandidate[word] && !isCandidate[best] {
+			best, bestBits = word, bits
+		}
+	}
+	return best, bestBits
+}
+
+// Analyze replays a finished board, grading every guess against the solutions that were still
+// possible. It is only available once the board is won or lost, since it narrows down the answer.
+func (s *Service) Analyze(board models.GameBoard) (*models.BoardAnalysis, error) {
+	if board.State != models.GameStateWon && board.State != models.GameStateLost {
+		return nil, nil
+	}
+	if board.Imported {
+		// imported boards only have patterns, there are no guesses to grade
+		return nil, nil
+	}
+
+	loadWords()
+	if board.Day < 0 || board.Day >= len(solutions) {
+		return nil, fmt.Errorf("no solution for day %d", board.Day)
+	}
+
+	analysis := &models.BoardAnalysis{
+		Day:  board.Day,
+		Rows: make([]models.RowAnalysis, 0, len(board.Guesses)),
+	}
+	candidates := solutions
+	for i, row := range board.Guesses {
+		guess := rowWord(row)
+		if len(guess) != 5 {
+			return nil, fmt.Errorf("row %d of day %d has no letters to analyze", i, board.Day)
+		}
+
+		var best string
+		var bestBits float64
+		if i == 0 {
+			openerBestOnce.Do(func() {
+				openerBest, openerBestBits = bestGuess(solutions)
+			})
+			best, bestBits = openerBest, openerBestBits
+		} else {
+			best, bestBits = bestGuesses.get(candidates)
+		}
+
+		remaining := filterCandidates(candidates, guess, rowToPattern(row))
+		bitsGained := 0.0
+		if len(remaining) > 0 {
+			bitsGained = math.Log2(float64(len(candidates)) / float64(len(remaining)))
+		}
+		analysis.Rows = append(analysis.Rows, models.RowAnalysis{
+			Guess:                 guess,
+			CandidatesBefore:      len(candidates),
+			CandidatesAfter:       len(remaining),
+			BitsGained:            bitsGained,
+			ExpectedBits:          expectedBits(candidates, guess),
+			BestGuess:             best,
+			BestGuessExpectedBits: bestBits,
+		})
+		candidates = remaining
+	}
+	return analysis, nil
+}
@@ -0,0 +1,128 @@
+package wordle
+
+import (
+	"github.com/amanzanero/wordleboard/api/models"
+)
+
+// A pattern is the feedback for one guess packed into a base 3 number, one digit per letter:
+// 0 for incorrect, 1 for in word and 2 for in location
+type pattern uint8
+
+const (
+	patternIncorrect = 0
+	patternInWord    = 1
+	patternInLoc     = 2
+
+	// solvedPattern is every letter in location
+	solvedPattern pattern = 242
+)
+
+// feedback scores a guess against a solution the same way scoreGuess does, without allocating
+func feedback(solution, guess string) pattern {
+	var states [5]uint8
+	var remaining [26]uint8
+	for i := 0; i < 5; i++ {
+		if guess[i] == solution[i] {
+			states[i] = patternInLoc
+		} else {
+			remaining[solution[i]-'a'] += 1
+		}
+	}
+	for i := 0; i < 5; i++ {
+		if states[i] == patternInLoc {
+			continue
+		}
+		if letter := guess[i] - 'a'; remaining[letter] > 0 {
+			remaining[letter] -= 1
+			states[i] = patternInWord
+		}
+	}
+
+	var p pattern
+	for i := 4; i >= 0; i-- {
+		p = p*3 + pattern(states[i])
+	}
+	return p
+}
+
+// digits unpacks a pattern into its per-letter states
+func (p pattern) digits() [5]uint8 {
+	var states [5]uint8
+	for i := 0; i < 5; i++ {
+		states[i] = uint8(p % 3)
+		p /= 3
+	}
+	return states
+}
+
+// weight is used to break ties between patterns, less revealing patterns weigh less
+func (p pattern) weight() int {
+	weight := 0
+	for _, state := range p.digits() {
+		weight += int(state)
+	}
+	return weight
+}
+
+// row converts a pattern back into the guess states shown on a board
+func (p pattern) row(guess string) []models.GuessState {
+	row := make([]models.GuessState, 5)
+	for i, state := range p.digits() {
+		row[i].Letter = string(guess[i])
+		switch state {
+		case patternInLoc:
+			row[i].Guess = models.LetterGuessInLocation
+		case patternInWord:
+			row[i].Guess = models.LetterGuessInWord
+		default:
+			row[i].Guess = models.LetterGuessIncorrect
+		}
+	}
+	return row
+}
+
+// rowToPattern packs a scored row back into a pattern
+func rowToPattern(row []models.GuessState) pattern {
+	var p pattern
+	for i := len(row) - 1; i >= 0; i-- {
+		var state pattern = patternIncorrect
+		switch row[i].Guess {
+		case models.LetterGuessInLocation:
+			state = patternInLoc
+		case models.LetterGuessInWord:
+			state = patternInWord
+		}
+		p = p*3 + state
+	}
+	return p
+}
+
+// partition groups candidate solutions by the feedback the guess would get against each of them
+func partition(candidates []string, guess string) map[pattern][]string {
+	buckets := make(map[pattern][]string)
+	for _, candidate := range candidates {
+		p := feedback(candidate, guess)
+		buckets[p] = append(buckets[p], candidate)
+	}
+	return buckets
+}
+
+// filterCandidates keeps the candidates that would have given a guess the same feedback
+func filterCandidates(candidates []string, guess string, p pattern) []string {
+	kept := make([]string, 0)
+	for _, candidate := range candidates {
+		if feedback(candidate, guess) == p {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept
+}
+
+// rowWord reads the guessed word back out of a scored row
+func rowWord(row []models.GuessState) string {
+	word := make([]byte, 0, len(row))
+	for _, state := range row {
+		word = append(word, state.Letter...)
+	}
+	return string(word)
+}
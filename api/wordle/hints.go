@@ -0,0 +1,96 @@
+package wordle
+
+import (
+	"context"
+	"github.com/amanzanero/wordleboard/api/models"
+	"time"
+)
+
+const (
+	// maxHints is how many hints a single board can get
+	maxHints = 2
+
+	// minHiddenPositions is how many positions have to stay unknown after a hint, so hints can
+	// never give away the whole solution
+	minHiddenPositions = 2
+)
+
+// nextHint derives the next hint of a kind for a board from its solution. Hints only reveal what
+// the guesses and earlier hints haven't already, one letter at a time.
+func nextHint(solution string, board models.GameBoard, kind models.HintKind, now time.Time) (*models.Hint, *models.HintResultError) {
+	if board.State != models.GameStateInProgress {
+		return nil, &models.HintResultError{Error: models.HintErrorGameOver}
+	}
+	if len(board.Hints) >= maxHints {
+		return nil, &models.HintResultError{Error: models.HintErrorNoHintsLeft}
+	}
+
+	knownPositions := make(map[int]bool)
+	knownLetters := make(map[string]bool)
+	for _, state := range LetterStates(board.Guesses) {
+		for _, position := range state.CorrectPositions {
+			knownPositions[position] = true
+		}
+		if state.MinCount > 0 {
+			knownLetters[state.Letter] = true
+		}
+	}
+	for _, hint := range board.Hints {
+		if hint.Position != nil {
+			knownPositions[*hint.Position] = true
+		}
+		knownLetters[hint.Letter] = true
+	}
+
+	if 5-len(knownPositions)-1 < minHiddenPositions {
+		return nil, &models.HintResultError{Error: models.HintErrorNoHintAvailable}
+	}
+
+	for i := 0; i < len(solution); i++ {
+		if knownPositions[i] {
+			continue
+		}
+		letter := string(solution[i])
+		switch kind {
+		case models.HintKindPosition:
+			position := i
+			return &models.Hint{Kind: kind, Letter: letter, Position: &position, RequestedAt: now}, nil
+		case models.HintKindLetter:
+			if !knownLetters[letter] {
+				return &models.Hint{Kind: kind, Letter: letter, RequestedAt: now}, nil
+			}
+		}
+	}
+	return nil, &models.HintResultError{Error: models.HintErrorNoHintAvailable}
+}
+
+// RequestHint adds a hint to today's board. Hints are recorded on the board, so leaderboards can
+// decide how they count. The hint is only saved if the board didn't change since it was read, so a
+// hint and a guess made at the same time can't overwrite each other or go past maxHints.
+func (s *Service) RequestHint(ctx context.Context, userId string, kind models.HintKind) (models.HintResult, error) {
+	loadWords()
+
+	now := time.Now().UTC()
+	today := timeToWordleDay(now)
+	for i := 0; i < boardUpdateRetries; i++ {
+		gameBoard, lookupErr := s.repo.FindGameBoardByUserAndDay(ctx, userId, today)
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+
+		hint, hintErr := nextHint(solutions[today], *gameBoard, kind, now)
+		if hintErr != nil {
+			return *hintErr, nil
+		}
+
+		gameBoard.Hints = append(gameBoard.Hints, *hint)
+		updateErr := s.repo.AddGameBoardHint(ctx, userId, *gameBoard)
+		if _, isConflict := updateErr.(models.ErrConflict); isConflict {
+			continue
+		} else if updateErr != nil {
+			return nil, updateErr
+		}
+		return hint, nil
+	}
+	return nil, models.ErrRepoFailed{RepoMethod: "RequestHint", Message: "the board kept changing while adding a hint"}
+}
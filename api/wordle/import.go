@@ -0,0 +1,158 @@
+package wordle
+
+import (
+	"context"
+	"github.com/amanzanero/wordleboard/api/models"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// shareHeader matches the first line of the official game's share text, like "Wordle 312 4/6" or
+// "Wordle 1,012 X/6*". The puzzle number is the same as our day, and the asterisk is hard mode.
+var shareHeader = regexp.MustCompile(`(?i)^wordle\s+([\d,.]+)\s+([1-6x])/6(\*?)$`)
+
+// shareSquares are the squares used by the default, dark, and high contrast share grids
+var shareSquares = map[rune]models.LetterGuess{
+	'🟩': models.LetterGuessInLocation,
+	'🟧': models.LetterGuessInLocation,
+	'🟨': models.LetterGuessInWord,
+	'🟦': models.LetterGuessInWord,
+	'⬛': models.LetterGuessIncorrect,
+	'⬜': models.LetterGuessIncorrect,
+}
+
+type sharedGame struct {
+	day      int
+	score    int // 0 for a lost game
+	hardMode bool
+	guesses  [][]models.GuessState
+}
+
+// parseShareText finds every shared game in pasted text. Games can be pasted back to back, and
+// anything that isn't a header or a grid row is ignored.
+func parseShareText(text string) []sharedGame {
+	games := make([]sharedGame, 0)
+	var current *sharedGame
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if match := shareHeader.FindStringSubmatch(line); match != nil {
+			day, dayErr := strconv.Atoi(strings.NewReplacer(",", "", ".", "").Replace(match[1]))
+			if dayErr != nil {
+				current = nil
+				continue
+			}
+			score, _ := strconv.Atoi(match[2]) // X is a loss
+			games = append(games, sharedGame{
+				day:      day,
+				score:    score,
+				hardMode: match[3] != "",
+				guesses:  make([][]models.GuessState, 0),
+			})
+			current = &games[len(games)-1]
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+		if row := parseShareRow(line); row != nil {
+			current.guesses = append(current.guesses, row)
+		} else if line != "" {
+			current = nil
+		}
+	}
+	return games
+}
+
+// parseShareRow reads a row of squares into pattern-only guess states, or nil if the line isn't a
+// row
+func parseShareRow(line string) []models.GuessState {
+	row := make([]models.GuessState, 0, 5)
+	for _, r := range line {
+		if r == '\uFE0F' || r == ' ' {
+			// variation selectors and spaces show up depending on where the text was copied
+			continue
+		}
+		state, ok := shareSquares[r]
+		if !ok {
+			return nil
+		}
+		row = append(row, models.GuessState{Guess: state})
+	}
+	if len(row) != 5 {
+		return nil
+	}
+	return row
+}
+
+// board checks that a shared game's grid agrees with its score, and turns it into a finished
+// board
+func (g sharedGame) board(now time.Time) (*models.GameBoard, bool) {
+	if len(g.guesses) == 0 || len(g.guesses) > 6 {
+		return nil, false
+	}
+	for i, row := range g.guesses {
+		solved := rowToPattern(row) == solvedPattern
+		if solved != (g.score != 0 && i == len(g.guesses)-1) {
+			return nil, false
+		}
+	}
+	if g.score == 0 && len(g.guesses) != 6 || g.score != 0 && g.score != len(g.guesses) {
+		return nil, false
+	}
+
+	state := models.GameStateWon
+	if g.score == 0 {
+		state = models.GameStateLost
+	}
+	return &models.GameBoard{
+		Day:        g.day,
+		Guesses:    g.guesses,
+		State:      state,
+		Imported:   true,
+		HardMode:   g.hardMode,
+		CreatedAt:  now,
+		GuessTimes: make([]time.Time, 0),
+	}, true
+}
+
+// ImportShareText stores games pasted from the official game's share text as imported boards.
+// Only past days can be imported, and a day that already has a board is never overwritten.
+func (s *Service) ImportShareText(ctx context.Context, userId, text string) (*models.ImportResult, error) {
+	now := time.Now().UTC()
+	today := timeToWordleDay(now)
+	result := &models.ImportResult{
+		Imported: make([]*models.GameBoard, 0),
+		Skipped:  make([]models.SkippedImport, 0),
+	}
+
+	for _, game := range parseShareText(text) {
+		if game.day < 0 || game.day >= today {
+			result.Skipped = append(result.Skipped, models.SkippedImport{Day: game.day, Reason: models.ImportSkipReasonNotInPast})
+			continue
+		}
+		board, valid := game.board(now)
+		if !valid {
+			result.Skipped = append(result.Skipped, models.SkippedImport{Day: game.day, Reason: models.ImportSkipReasonInvalidGrid})
+			continue
+		}
+
+		insertErr := s.repo.InsertGameBoardIfMissing(ctx, userId, *board)
+		if _, alreadyPlayed := insertErr.(models.ErrNotFound); alreadyPlayed {
+			result.Skipped = append(result.Skipped, models.SkippedImport{Day: game.day, Reason: models.ImportSkipReasonAlreadyPlayed})
+			continue
+		} else if insertErr != nil {
+			return nil, insertErr
+		}
+		result.Imported = append(result.Imported, board)
+	}
+
+	if len(result.Imported) > 0 {
+		if _, statsErr := s.recorder.RecomputeStatistics(ctx, userId); statsErr != nil {
+			s.logger.Errorf("could not recompute statistics for user %s after import: %v", userId, statsErr)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,88 @@
+package wordle
+
+import (
+	"github.com/amanzanero/wordleboard/api/models"
+	"sort"
+)
+
+type letterKnowledge struct {
+	correct  map[int]bool
+	excluded map[int]bool
+	minCount int
+	maxCount *int
+}
+
+// LetterStates works out the best known state of every guessed letter from a board's guesses, along
+// with the constraints on where it can go and how many times it appears. Letters come back in
+// alphabetical order, and positions are 0 indexed.
+func LetterStates(guessRows [][]models.GuessState) []models.LetterState {
+	knowledge := make(map[string]*letterKnowledge)
+	for _, row := range guessRows {
+		marked := make(map[string]int)
+		hasIncorrect := make(map[string]bool)
+		for position, state := range row {
+			if state.Letter == "" {
+				continue
+			}
+			known, ok := knowledge[state.Letter]
+			if !ok {
+				known = &letterKnowledge{correct: make(map[int]bool), excluded: make(map[int]bool)}
+				knowledge[state.Letter] = known
+			}
+
+			switch state.Guess {
+			case models.LetterGuessInLocation:
+				known.correct[position] = true
+				marked[state.Letter] += 1
+			case models.LetterGuessInWord:
+				known.excluded[position] = true
+				marked[state.Letter] += 1
+			default:
+				known.excluded[position] = true
+				hasIncorrect[state.Letter] = true
+			}
+		}
+
+		for letter, count := range marked {
+			if count > knowledge[letter].minCount {
+				knowledge[letter].minCount = count
+			}
+		}
+		for letter := range hasIncorrect {
+			// an incorrect copy of a letter means the row marked every copy in the solution
+			exact := marked[letter]
+			knowledge[letter].maxCount = &exact
+		}
+	}
+
+	states := make([]models.LetterState, 0, len(knowledge))
+	for letter, known := range knowledge {
+		state := models.LetterState{
+			Letter:            letter,
+			State:             models.LetterGuessIncorrect,
+			CorrectPositions:  sortedPositions(known.correct),
+			ExcludedPositions: sortedPositions(known.excluded),
+			MinCount:          known.minCount,
+			MaxCount:          known.maxCount,
+		}
+		if len(known.correct) > 0 {
+			state.State = models.LetterGuessInLocation
+		} else if known.minCount > 0 {
+			state.State = models.LetterGuessInWord
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Letter < states[j].Letter
+	})
+	return states
+}
+
+func sortedPositions(positions map[int]bool) []int {
+	sorted := make([]int, 0, len(positions))
+	for position := range positions {
+		sorted = append(sorted, position)
+	}
+	sort.Ints(sorted)
+	return sorted
+}
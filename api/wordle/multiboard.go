@@ -0,0 +1,106 @@
+package wordle
+
+import (
+	"context"
+	"github.com/amanzanero/wordleboard/api/models"
+	"math/rand"
+	"time"
+)
+
+// multiBoardSolutions picks the solutions for a multi-board day. Every player gets the same words
+// for a day and variant, and they're only drawn from past daily solutions so upcoming answers stay
+// hidden.
+func multiBoardSolutions(day int, variant models.MultiBoardVariant) []string {
+	pastDays := day
+	if pastDays > len(solutions) {
+		pastDays = len(solutions)
+	}
+
+	seed := int64(day)*int64(len(models.AllMultiBoardVariant)) + int64(variant.Boards())
+	picks := rand.New(rand.NewSource(seed)).Perm(pastDays)[:variant.Boards()]
+
+	words := make([]string, len(picks))
+	for i, pick := range picks {
+		words[i] = solutions[pick]
+	}
+	return words
+}
+
+func (s *Service) GetTodayMultiBoardOrCreate(ctx context.Context, userId string, variant models.MultiBoardVariant, t time.Time) (*models.MultiBoard, error) {
+	day := timeToWordleDay(t)
+	board, lookupErr := s.repo.FindMultiBoardByUserAndDay(ctx, userId, day, variant)
+	if lookupErr == nil {
+		return board, nil
+	} else if _, isNotFound := lookupErr.(models.ErrNotFound); !isNotFound {
+		return nil, lookupErr
+	}
+
+	multiBoard := models.MultiBoard{
+		Day:     day,
+		Variant: variant,
+		Boards:  make([]models.SubBoard, variant.Boards()),
+		State:   models.GameStateInProgress,
+	}
+	for i := range multiBoard.Boards {
+		multiBoard.Boards[i] = models.SubBoard{
+			Guesses: make([][]models.GuessState, 0),
+			State:   models.GameStateInProgress,
+		}
+	}
+	insertErr := s.repo.InsertMultiBoard(ctx, userId, multiBoard)
+	if insertErr != nil {
+		return nil, insertErr
+	}
+	return &multiBoard, nil
+}
+
+// MultiGuess plays one guess against every unsolved sub-board of today's multi-board
+func (s *Service) MultiGuess(ctx context.Context, userId string, variant models.MultiBoardVariant, guess string) (models.GuessResult, error) {
+	loadWords()
+
+	board, lookupErr := s.GetTodayMultiBoardOrCreate(ctx, userId, variant, time.Now())
+	if lookupErr != nil {
+		return nil, lookupErr
+	}
+
+	if board.State != models.GameStateInProgress {
+		return board, nil
+	}
+
+	if invalid := validateGuess(guess); invalid != nil {
+		return *invalid, nil
+	}
+
+	daySolutions := multiBoardSolutions(board.Day, variant)
+	allSolved := true
+	for i := range board.Boards {
+		subBoard := &board.Boards[i]
+		if subBoard.State != models.GameStateInProgress {
+			continue
+		}
+		newGuess, guessWasSolution := scoreGuess(daySolutions[i], guess)
+		subBoard.Guesses = append(subBoard.Guesses, newGuess)
+		if guessWasSolution {
+			subBoard.State = models.GameStateWon
+		} else {
+			allSolved = false
+		}
+	}
+
+	if allSolved {
+		board.State = models.GameStateWon
+	} else if board.GuessCount() == variant.MaxGuesses() {
+		board.State = models.GameStateLost
+		for i := range board.Boards {
+			if board.Boards[i].State == models.GameStateInProgress {
+				board.Boards[i].State = models.GameStateLost
+			}
+		}
+	}
+
+	updateErr := s.repo.UpdateMultiBoard(ctx, userId, *board)
+	if updateErr != nil {
+		return nil, updateErr
+	}
+	return board, nil
+}
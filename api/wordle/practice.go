@@ -0,0 +1,76 @@
+package wordle
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"github.com/amanzanero/wordleboard/api/models"
+	"github.com/lithammer/shortuuid/v4"
+	"math/big"
+	"time"
+)
+
+// StartPractice creates a practice board for a random solution. Only solutions from days that have
+// already passed are drawn, so practice never spoils today's or an upcoming daily answer.
+func (s *Service) StartPractice(ctx context.Context, userId string, t time.Time) (*models.PracticeBoard, error) {
+	loadWords()
+
+	pastDays := timeToWordleDay(t)
+	if pastDays > len(solutions) {
+		pastDays = len(solutions)
+	}
+	if pastDays <= 0 {
+		return nil, errors.New("no past solutions available for practice")
+	}
+	index, randErr := rand.Int(rand.Reader, big.NewInt(int64(pastDays)))
+	if randErr != nil {
+		return nil, randErr
+	}
+
+	board := models.PracticeBoard{
+		ID:      shortuuid.New(),
+		UserId:  userId,
+		Word:    solutions[index.Int64()],
+		Guesses: make([][]models.GuessState, 0),
+		State:   models.GameStateInProgress,
+	}
+	insertErr := s.repo.InsertPracticeBoard(ctx, board)
+	if insertErr != nil {
+		return nil, insertErr
+	}
+	return &board, nil
+}
+
+func (s *Service) GetPracticeBoard(ctx context.Context, userId, id string) (*models.PracticeBoard, error) {
+	return s.repo.FindPracticeBoard(ctx, userId, id)
+}
+
+// PracticeGuess plays a guess on one of the user's practice boards. Practice boards live apart from
+// the daily boards, so they never show up in leaderboard stats.
+func (s *Service) PracticeGuess(ctx context.Context, userId, id, guess string) (models.GuessResult, error) {
+	loadWords()
+
+	board, lookupErr := s.repo.FindPracticeBoard(ctx, userId, id)
+	if lookupErr != nil {
+		return nil, lookupErr
+	}
+
+	// is game done?
+	if board.State != models.GameStateInProgress {
+		return board, nil
+	}
+
+	if invalid := validateGuess(guess); invalid != nil {
+		return *invalid, nil
+	}
+
+	newGuess, guessWasSolution := scoreGuess(board.Word, guess)
+	board.Guesses = append(board.Guesses, newGuess)
+	board.State = nextGameState(board.Guesses, guessWasSolution)
+
+	updateErr := s.repo.UpdatePracticeBoard(ctx, *board)
+	if updateErr != nil {
+		return nil, updateErr
+	}
+	return board, nil
+}
@@ -0,0 +1,68 @@
+package wordle
+
+import (
+	"context"
+	"github.com/amanzanero/wordleboard/api/models"
+	"github.com/lithammer/shortuuid/v4"
+	"strings"
+	"time"
+)
+
+// CreatePuzzle creates a custom puzzle with a hand-picked solution. The solution has to be a word
+// from the same dictionary that guesses are validated against.
+func (s *Service) CreatePuzzle(ctx context.Context, userId, solution string) (models.PuzzleResult, error) {
+	loadWords()
+
+	solution = strings.ToLower(strings.TrimSpace(solution))
+	if invalid := validateGuess(solution); invalid != nil {
+		return *invalid, nil
+	}
+
+	puzzle := models.Puzzle{
+		ID:           shortuuid.New(),
+		Creator:      userId,
+		Word:         solution,
+		Leaderboards: make([]string, 0),
+		CreatedAt:    time.Now().UTC(),
+		Boards:       make([]models.PuzzleBoard, 0),
+	}
+	insertErr := s.repo.InsertPuzzle(ctx, puzzle)
+	if insertErr != nil {
+		return nil, insertErr
+	}
+	return &puzzle, nil
+}
+
+func (s *Service) GetPuzzle(ctx context.Context, id string) (*models.Puzzle, error) {
+	return s.repo.FindPuzzle(ctx, id)
+}
+
+// PuzzleGuess plays a guess on the user's board for a custom puzzle, using the same scoring as the
+// daily board. Anyone with the puzzle's id can play it.
+func (s *Service) PuzzleGuess(ctx context.Context, userId, id, guess string) (models.GuessResult, error) {
+	loadWords()
+
+	puzzle, lookupErr := s.repo.FindPuzzle(ctx, id)
+	if lookupErr != nil {
+		return nil, lookupErr
+	}
+
+	board := puzzle.BoardForUser(userId)
+	if board.State != models.GameStateInProgress {
+		return board, nil
+	}
+
+	if invalid := validateGuess(guess); invalid != nil {
+		return *invalid, nil
+	}
+
+	newGuess, guessWasSolution := scoreGuess(puzzle.Word, guess)
+	board.Guesses = append(board.Guesses, newGuess)
+	board.State = nextGameState(board.Guesses, guessWasSolution)
+
+	updateErr := s.repo.UpsertPuzzleBoard(ctx, board)
+	if updateErr != nil {
+		return nil, updateErr
+	}
+	return board, nil
+}
@@ -0,0 +1,59 @@
+package wordle
+
+import (
+	"fmt"
+	"github.com/amanzanero/wordleboard/api/models"
+	"strings"
+)
+
+// paletteSquares are the squares for incorrect, in word, and in location letters
+var paletteSquares = map[models.SharePalette][3]string{
+	models.SharePaletteDefault:      {"⬛", "🟨", "🟩"},
+	models.SharePaletteLight:        {"⬜", "🟨", "🟩"},
+	models.SharePaletteHighContrast: {"⬛", "🟦", "🟧"},
+}
+
+// ShareText is the spoiler-free summary of a finished board, in the same format as the official
+// game so it can be pasted anywhere. Branded text adds the leaderboard's name at the bottom. It is
+// nil until the board is won or lost.
+func ShareText(board models.GameBoard, palette models.SharePalette, brand string) *string {
+	if board.State != models.GameStateWon && board.State != models.GameStateLost {
+		return nil
+	}
+	squares, ok := paletteSquares[palette]
+	if !ok {
+		squares = paletteSquares[models.SharePaletteDefault]
+	}
+
+	score := "X"
+	if board.State == models.GameStateWon {
+		score = fmt.Sprint(len(board.Guesses))
+	}
+
+	hardMode := ""
+	if board.HardMode {
+		hardMode = "*"
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Wordle %d %s/6%s\n", board.Day, score, hardMode)
+	for _, row := range board.Guesses {
+		text.WriteString("\n")
+		for _, state := range row {
+			switch state.Guess {
+			case models.LetterGuessInLocation:
+				text.WriteString(squares[2])
+			case models.LetterGuessInWord:
+				text.WriteString(squares[1])
+			default:
+				text.WriteString(squares[0])
+			}
+		}
+	}
+	if brand != "" {
+		fmt.Fprintf(&text, "\n\n%s on wordleboard", brand)
+	}
+
+	shareText := text.String()
+	return &shareText
+}
@@ -9,6 +9,10 @@ import (
 	"time"
 )
 
+// boardUpdateRetries is how many times a guess or hint is retried when the board changed while it
+// was being added
+const boardUpdateRetries = 3
+
 var (
 	day1     time.Time // June 19, 2021
 	day1Once sync.Once
@@ -19,21 +23,30 @@ var (
 )
 
 type Service struct {
-	logger *logrus.Logger
-	repo   models.GameBoardRepo
+	logger       *logrus.Logger
+	repo         models.GameBoardRepo
+	recorder     models.GameResultRecorder
+	achievements models.AchievementEvaluator
 }
 
 func NewService(
 	repo models.GameBoardRepo,
+	recorder models.GameResultRecorder,
+	achievements models.AchievementEvaluator,
 	logger *logrus.Logger,
 ) Service {
 	return Service{
-		repo:   repo,
-		logger: logger,
+		repo:         repo,
+		recorder:     recorder,
+		achievements: achievements,
+		logger:       logger,
 	}
 }
 
-func (s *Service) GetTodayGameOrCreateNewGame(ctx context.Context, userId string, t time.Time) (*models.GameBoard, error) {
+// GetTodayGameOrCreateNewGame finds the user's board for the day of t, and starts it if they don't
+// have one yet. A board that's started is in hard mode if the user prefers it.
+func (s *Service) GetTodayGameOrCreateNewGame(ctx context.Context, user models.User, t time.Time) (*models.GameBoard, error) {
+	userId := user.ID
 	// find today's if it already exists
 	day := timeToWordleDay(t)
 	board, lookupErr := s.repo.FindGameBoardByUserAndDay(ctx, userId, day)
@@ -45,9 +58,12 @@ func (s *Service) GetTodayGameOrCreateNewGame(ctx context.Context, userId string
 
 	// if none exists make a new board
 	gameBoard := models.GameBoard{
-		Day:     day,
-		Guesses: make([][]models.GuessState, 0),
-		State:   models.GameStateInProgress,
+		Day:        day,
+		Guesses:    make([][]models.GuessState, 0),
+		State:      models.GameStateInProgress,
+		CreatedAt:  t.UTC(),
+		GuessTimes: make([]time.Time, 0),
+		HardMode:   user.Preferences.HardModeDefault,
 	}
 	insertErr := s.repo.InsertGameBoard(ctx, userId, gameBoard)
 	if insertErr != nil {
@@ -67,6 +83,79 @@ func (s *Service) GetGameByDay(ctx context.Context, userId string, day int) (*mo
 }
 
 func (s *Service) Guess(ctx context.Context, userId, guess string) (models.GuessResult, error) {
+	loadWords()
+
+	now := time.Now().UTC()
+	today := timeToWordleDay(now)
+	for i := 0; i < boardUpdateRetries; i++ {
+		// today's board
+		gameBoard, lookupErr := s.repo.FindGameBoardByUserAndDay(ctx, userId, today)
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+
+		// is game done?
+		if gameBoard.State != models.GameStateInProgress {
+			return gameBoard, nil
+		}
+
+		// see if guess is valid
+		if invalid := validateGuess(guess); invalid != nil {
+			return *invalid, nil
+		}
+		if gameBoard.HardMode && !usesRevealedLetters(guess, gameBoard.Guesses) {
+			return models.InvalidGuess{Error: models.GuessErrorHardMode}, nil
+		}
+
+		newGuess, guessWasSolution := scoreGuess(solutions[today], guess)
+		gameBoard.Guesses = append(gameBoard.Guesses, newGuess)
+		gameBoard.State = nextGameState(gameBoard.Guesses, guessWasSolution)
+
+		// record when the guess was played, durations are only ever computed from these
+		gameBoard.GuessTimes = append(gameBoard.GuessTimes, now)
+		if gameBoard.FirstGuessAt == nil {
+			gameBoard.FirstGuessAt = &now
+		}
+		if gameBoard.State != models.GameStateInProgress {
+			gameBoard.CompletedAt = &now
+		}
+
+		// only the guess is saved, and only if the board didn't change since it was read
+		updateErr := s.repo.AddGameBoardGuess(ctx, userId, *gameBoard)
+		if _, isConflict := updateErr.(models.ErrConflict); isConflict {
+			continue
+		} else if updateErr != nil {
+			return nil, updateErr
+		}
+
+		if gameBoard.State != models.GameStateInProgress {
+			// the guess already counted. Statistics that missed the game are cleared, so they're
+			// recomputed from the boards the next time they're read.
+			if recordErr := s.recorder.RecordGameResult(ctx, userId, *gameBoard); recordErr != nil {
+				s.logger.Errorf("failed to record game result: %v", recordErr)
+				if clearErr := s.recorder.ClearStatistics(ctx, userId); clearErr != nil {
+					s.logger.Errorf("failed to clear statistics: %v", clearErr)
+				}
+			}
+
+			unlocked, achievementsErr := s.achievements.EvaluateGame(ctx, userId, *gameBoard)
+			if achievementsErr != nil {
+				s.logger.Errorf("failed to evaluate achievements: %v", achievementsErr)
+			}
+			gameBoard.UnlockedAchievements = unlocked
+		}
+		return gameBoard, nil
+	}
+	return nil, models.ErrRepoFailed{RepoMethod: "Guess", Message: "the board kept changing while adding a guess"}
+}
+
+// DayOf returns the wordle day that t falls on
+func DayOf(t time.Time) int {
+	return timeToWordleDay(t)
+}
+
+// loadWords reads the guess dictionary and solution list from disk the first time it is called
+func loadWords() {
 	solutionsOnce.Do(func() {
 		_guesses, err := loadGuesses()
 		if err != nil {
@@ -80,70 +169,86 @@ func (s *Service) Guess(ctx context.Context, userId, guess string) (models.Guess
 		}
 		solutions = _solutions
 	})
+}
 
-	// today's board
-	today := timeToWordleDay(time.Now())
-	gameBoard, lookupErr := s.repo.FindGameBoardByUserAndDay(ctx, userId, today)
-	if lookupErr != nil {
-		return nil, lookupErr
-	}
-
-	// is game done?
-	if gameBoard.State != models.GameStateInProgress {
-		return gameBoard, nil
+// GetSolution finds a day's solution, or nil if there isn't a solution for the day
+func (s *Service) GetSolution(day int) *string {
+	loadWords()
+	if day < 0 || day >= len(solutions) {
+		return nil
 	}
+	solution := solutions[day]
+	return &solution
+}
 
-	// see if guess is valid
+// validateGuess returns the reason a guess can't be played, or nil if it is a valid word
+func validateGuess(guess string) *models.InvalidGuess {
 	if len(guess) != 5 {
-		return models.InvalidGuess{Error: models.GuessErrorInvalidLength}, nil
+		return &models.InvalidGuess{Error: models.GuessErrorInvalidLength}
+	}
+	if _, ok := guesses[guess]; !ok {
+		return &models.InvalidGuess{Error: models.GuessErrorNotAWord}
 	}
+	return nil
+}
 
-	// is this a word?
-	if _, ok := guesses[guess]; ok {
-		solution := solutions[today]
-		newGuess := make([]models.GuessState, 5)
-		solutionGuessState := createGuessState(solution)
-
-		for i, l := range guess {
-			letter := string(l)
-			if letter == string(solution[i]) {
-				newGuess[i].Guess = models.LetterGuessInLocation
-				solutionGuessState[i].correctOrInWord = true
+// usesRevealedLetters checks a guess against the hard mode rules: letters found in the right place
+// have to stay there, and letters found in the word have to be used
+func usesRevealedLetters(guess string, guessRows [][]models.GuessState) bool {
+	for _, state := range LetterStates(guessRows) {
+		for _, position := range state.CorrectPositions {
+			if string(guess[position]) != state.Letter {
+				return false
 			}
 		}
-
-		guessWasSolution := true
-		for i, l := range guess {
-			letter := string(l)
-			newGuess[i].Letter = string(l)
-			inWord := strings.Contains(solution, letter)
-
-			if letter == string(solution[i]) {
-				// nothing
-			} else if inWord && hasRemainingLetterAndMarkUsed(solutionGuessState, letter) {
-				guessWasSolution = false
-				newGuess[i].Guess = models.LetterGuessInWord
-			} else {
-				guessWasSolution = false
-				newGuess[i].Guess = models.LetterGuessIncorrect
-			}
+		if strings.Count(guess, state.Letter) < state.MinCount {
+			return false
 		}
-		gameBoard.Guesses = append(gameBoard.Guesses, newGuess)
+	}
+	return true
+}
 
-		// evaluate winning state
-		if guessWasSolution {
-			gameBoard.State = models.GameStateWon
-		} else if len(gameBoard.Guesses) == 6 {
-			gameBoard.State = models.GameStateLost
+// scoreGuess compares a guess to the solution, returning the row of letter states and whether
+// the guess was the solution
+func scoreGuess(solution, guess string) ([]models.GuessState, bool) {
+	newGuess := make([]models.GuessState, 5)
+	solutionGuessState := createGuessState(solution)
+
+	for i, l := range guess {
+		letter := string(l)
+		if letter == string(solution[i]) {
+			newGuess[i].Guess = models.LetterGuessInLocation
+			solutionGuessState[i].correctOrInWord = true
 		}
-		updateErr := s.repo.UpdateGameBoardByUserAndDay(ctx, today, userId, *gameBoard)
-		if updateErr != nil {
-			return nil, updateErr
+	}
+
+	guessWasSolution := true
+	for i, l := range guess {
+		letter := string(l)
+		newGuess[i].Letter = string(l)
+		inWord := strings.Contains(solution, letter)
+
+		if letter == string(solution[i]) {
+			// nothing
+		} else if inWord && hasRemainingLetterAndMarkUsed(solutionGuessState, letter) {
+			guessWasSolution = false
+			newGuess[i].Guess = models.LetterGuessInWord
+		} else {
+			guessWasSolution = false
+			newGuess[i].Guess = models.LetterGuessIncorrect
 		}
-		return gameBoard, nil
-	} else {
-		return models.InvalidGuess{Error: models.GuessErrorNotAWord}, nil
 	}
+	return newGuess, guessWasSolution
+}
+
+// nextGameState evaluates the winning state of a board after its latest guess
+func nextGameState(guesses [][]models.GuessState, guessWasSolution bool) models.GameState {
+	if guessWasSolution {
+		return models.GameStateWon
+	} else if len(guesses) == 6 {
+		return models.GameStateLost
+	}
+	return models.GameStateInProgress
 }
 
 type guessAtLocation struct {